@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/config"
+	"io/fs"
+)
+
+// exportStatic runs `go-blog export-static -o ./public`, it renders the published site to static files.
+func exportStatic(viperConfig *viper.Viper, log *logrus.Logger, theme fs.FS, args []string) {
+	flags := flag.NewFlagSet("export-static", flag.ExitOnError)
+	output := flags.String("o", "./public", "output directory")
+	_ = flags.Parse(args)
+
+	db := config.NewDatabase(viperConfig, log)
+	result, err := config.NewStaticSite(db, log, viperConfig, theme).Export(context.Background(), *output)
+	if err != nil {
+		log.Fatalf("Failed to export static site: %v", err)
+	}
+	log.Infof("Exported static site to %s: %d written, %d unchanged, %d removed", *output, result.Written, result.Unchanged, result.Removed)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gofiber/swagger"
 	_ "go-blog/docs"
 	"go-blog/internal/config"
+	"os"
 )
 
 // @title                       Go-blog Backend
@@ -26,7 +27,13 @@ import (
 func main() {
 	viperConfig := config.NewViper()
 	log := config.NewLogger(viperConfig)
-	app := config.NewFiber(viperConfig)
+	theme := config.NewTheme(viperConfig, log)
+	if len(os.Args) > 1 && os.Args[1] == "export-static" {
+		exportStatic(viperConfig, log, theme, os.Args[2:])
+		return
+	}
+
+	app := config.NewFiber(viperConfig, theme)
 	db := config.NewDatabase(viperConfig, log)
 	validate := config.NewValidator()
 
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -11,6 +12,7 @@ import (
 	"go-blog/internal/repository"
 	"go-blog/internal/usecase"
 	"gorm.io/gorm"
+	"time"
 )
 
 type BootstrapConfig struct {
@@ -29,15 +31,80 @@ func Bootstrap(config *BootstrapConfig) {
 	userRepository := repository.NewUserRepository(config.Log)
 	postRepository := repository.NewPostRepository(config.Log)
 	tagRepository := repository.NewTagRepository(config.Log)
+	loginThrottleRepository := repository.NewLoginThrottleRepository(config.Log)
+	emailChangeRepository := repository.NewEmailChangeRepository(config.Log)
+	usernameHistoryRepository := repository.NewUsernameHistoryRepository(config.Log)
+	accountDeletionRepository := repository.NewAccountDeletionRepository(config.Log)
+	dataExportRepository := repository.NewDataExportRepository(config.Log)
+	commentRepository := repository.NewCommentRepository(config.Log)
+	reactionRepository := repository.NewReactionRepository(config.Log)
+	bookmarkRepository := repository.NewBookmarkRepository(config.Log)
+	readingListRepository := repository.NewReadingListRepository(config.Log)
+	seriesRepository := repository.NewSeriesRepository(config.Log)
+	postAuthorRepository := repository.NewPostAuthorRepository(config.Log)
+	followRepository := repository.NewFollowRepository(config.Log)
+	notificationRepository := repository.NewNotificationRepository(config.Log)
+	mediaRepository := repository.NewMediaRepository(config.Log)
+
+	passwordPolicy := NewPasswordPolicy(config.Config)
+	mailer := NewMailer(config.Config, config.Log)
+	spamFilter := NewSpamFilter(config.Config)
+	hub := NewRealtimeHub(config.Config, config.Log)
+	store := NewStorage(config.Config, config.Log, config.App)
 
 	// Setup use case
-	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, userRepository, authMiddleware)
-	postUseCase := usecase.NewPostUseCase(config.DB, config.Log, config.Validate, postRepository, tagRepository, userRepository)
+	loginThrottleUseCase := usecase.NewLoginThrottleUseCase(config.DB, config.Log, config.Validate, config.Config, loginThrottleRepository)
+	notificationUseCase := usecase.NewNotificationUseCase(config.DB, config.Log, config.Validate, config.Config, notificationRepository, followRepository, userRepository, mailer)
+	userUseCase := usecase.NewUserUseCase(config.DB, config.Log, config.Validate, config.Config, userRepository, postRepository, commentRepository, reactionRepository, bookmarkRepository, readingListRepository, seriesRepository, postAuthorRepository, followRepository, emailChangeRepository, usernameHistoryRepository, accountDeletionRepository, mediaRepository, store, authMiddleware, loginThrottleUseCase, notificationUseCase, passwordPolicy, mailer, hub)
+	postUseCase := usecase.NewPostUseCase(config.DB, config.Log, config.Validate, config.Config, postRepository, tagRepository, userRepository, usernameHistoryRepository, reactionRepository, bookmarkRepository, mediaRepository, seriesRepository, postAuthorRepository, notificationUseCase, hub)
+	exportUseCase := usecase.NewExportUseCase(config.DB, config.Log, config.Validate, config.Config, userRepository, postRepository, usernameHistoryRepository, emailChangeRepository, dataExportRepository)
+	commentUseCase := usecase.NewCommentUseCase(config.DB, config.Log, config.Validate, config.Config, commentRepository, postRepository, userRepository, spamFilter, hub)
+	reactionUseCase := usecase.NewReactionUseCase(config.DB, config.Log, config.Validate, reactionRepository, postRepository, hub)
+	bookmarkUseCase := usecase.NewBookmarkUseCase(config.DB, config.Log, config.Validate, bookmarkRepository, postRepository)
+	readingListUseCase := usecase.NewReadingListUseCase(config.DB, config.Log, config.Validate, config.Config, readingListRepository, postRepository, userRepository, usernameHistoryRepository)
+	postAuthorUseCase := usecase.NewPostAuthorUseCase(config.DB, config.Log, config.Validate, postAuthorRepository, postRepository, userRepository, notificationUseCase)
+	seriesUseCase := usecase.NewSeriesUseCase(config.DB, config.Log, config.Validate, config.Config, seriesRepository, postRepository, userRepository, usernameHistoryRepository)
+	followUseCase := usecase.NewFollowUseCase(config.DB, config.Log, config.Validate, followRepository, userRepository, tagRepository)
+	syndicationUseCase := usecase.NewSyndicationUseCase(config.DB, config.Log, config.Config, postRepository, userRepository, tagRepository, seriesRepository, usernameHistoryRepository)
+	sitemapUseCase := usecase.NewSitemapUseCase(config.DB, config.Log, config.Config, postRepository, tagRepository, userRepository, seriesRepository)
+	mediaUseCase := usecase.NewMediaUseCase(config.DB, config.Log, config.Validate, config.Config, mediaRepository, store)
+	siteUseCase := usecase.NewSiteUseCase(config.DB, config.Log, config.Config, postRepository, tagRepository, userRepository, usernameHistoryRepository, seriesRepository)
 	_ = usecase.NewTagUseCase(config.DB, config.Log, config.Validate, tagRepository)
 
 	// Setup controller
-	userController := http.NewUserController(config.Log, userUseCase)
+	userController := http.NewUserController(config.Log, userUseCase, loginThrottleUseCase)
 	postController := http.NewPostController(config.Log, postUseCase)
+	postAuthorController := http.NewPostAuthorController(config.Log, postAuthorUseCase)
+	exportController := http.NewExportController(config.Log, exportUseCase)
+	commentController := http.NewCommentController(config.Log, commentUseCase)
+	reactionController := http.NewReactionController(config.Log, reactionUseCase)
+	bookmarkController := http.NewBookmarkController(config.Log, bookmarkUseCase)
+	readingListController := http.NewReadingListController(config.Log, readingListUseCase)
+	seriesController := http.NewSeriesController(config.Log, seriesUseCase)
+	followController := http.NewFollowController(config.Log, followUseCase)
+	notificationController := http.NewNotificationController(config.Log, notificationUseCase)
+	syndicationController := http.NewSyndicationController(config.Log, syndicationUseCase)
+	sitemapController := http.NewSitemapController(config.Log, sitemapUseCase)
+	siteController := http.NewSiteController(config.Log, siteUseCase)
+	mediaController := http.NewMediaController(config.Log, mediaUseCase)
+	heartbeat := config.Config.GetInt("SSE_HEARTBEAT_SECONDS")
+	if heartbeat <= 0 {
+		heartbeat = 15
+	}
+	eventController := http.NewEventController(config.Log, hub, time.Duration(heartbeat)*time.Second)
+
+	// Setup background jobs
+	RunEvery(time.Hour, func(ctx context.Context) {
+		_ = userUseCase.PurgeDeleted(ctx)
+	})
+	RunEvery(15*time.Minute, func(ctx context.Context) {
+		_ = exportUseCase.Cleanup(ctx)
+	})
+	if config.Config.GetBool("NOTIFICATION_DIGEST_ENABLED") {
+		RunEvery(time.Hour*time.Duration(max(config.Config.GetInt("NOTIFICATION_DIGEST_HOURS"), 1)), func(ctx context.Context) {
+			_ = notificationUseCase.SendDigests(ctx)
+		})
+	}
 
 	// Testing route
 	config.App.Get("/ping", func(ctx *fiber.Ctx) error {
@@ -46,11 +113,25 @@ func Bootstrap(config *BootstrapConfig) {
 
 	// setup route
 	routeConfig := route.RouteConfig{
-		App:            config.App,
-		UserController: userController,
-		PostController: postController,
-		AuthMiddleware: authMiddleware,
-		Config:         config.Config,
+		App:                    config.App,
+		UserController:         userController,
+		PostController:         postController,
+		PostAuthorController:   postAuthorController,
+		ExportController:       exportController,
+		CommentController:      commentController,
+		ReactionController:     reactionController,
+		BookmarkController:     bookmarkController,
+		ReadingListController:  readingListController,
+		SeriesController:       seriesController,
+		FollowController:       followController,
+		NotificationController: notificationController,
+		EventController:        eventController,
+		SyndicationController:  syndicationController,
+		SitemapController:      sitemapController,
+		SiteController:         siteController,
+		MediaController:        mediaController,
+		AuthMiddleware:         authMiddleware,
+		Config:                 config.Config,
 	}
 
 	routeConfig.Setup()
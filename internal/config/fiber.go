@@ -1,27 +1,62 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/template/html/v2"
+	"github.com/gofiber/fiber/v2/middleware/filesystem"
 	"github.com/spf13/viper"
+	"go-blog/internal/model"
 	"go-blog/version"
+	"io/fs"
+	"net/http"
 )
 
-func NewFiber(config *viper.Viper) *fiber.App {
-	engine := html.New("./views", ".html")
+func NewFiber(config *viper.Viper, theme fs.FS) *fiber.App {
 	var app = fiber.New(fiber.Config{
 		AppName:      fmt.Sprintf("%s v%s", config.GetString("APP_NAME"), version.Version),
 		ErrorHandler: NewErrorHandler(),
 		Prefork:      config.GetBool("APP_PREFORK"),
-		Views:        engine,
+		Views:        NewViewEngine(config, theme),
+		BodyLimit:    bodyLimit(config),
 	})
 
+	// Asset URLs carry the version, so outside development they can be cached for long.
+	assets, err := fs.Sub(theme, "assets")
+	if err != nil {
+		panic(err)
+	}
+	maxAge := 7 * 24 * 60 * 60
+	if isDevelopment(config) {
+		maxAge = 0
+	}
+	app.Use("/assets", filesystem.New(filesystem.Config{
+		Root:   http.FS(assets),
+		MaxAge: maxAge,
+	}))
+
 	return app
 }
 
+// bodyLimit leaves room for an upload of MEDIA_MAX_SIZE_MB and its multipart framing.
+func bodyLimit(config *viper.Viper) int {
+	size := config.GetInt("MEDIA_MAX_SIZE_MB")
+	if size <= 0 {
+		size = 10
+	}
+	return (size + 1) << 20
+}
+
 func NewErrorHandler() fiber.ErrorHandler {
 	return func(ctx *fiber.Ctx, err error) error {
+		var validationErr *model.ValidationError
+		if errors.As(err, &validationErr) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"errors": err.Error(),
+				"fields": validationErr.Fields,
+			})
+		}
+
 		code := fiber.StatusInternalServerError
 		if e, ok := err.(*fiber.Error); ok {
 			code = e.Code
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/gateway/mail"
+)
+
+func NewMailer(viper *viper.Viper, log *logrus.Logger) mail.Mailer {
+	host := viper.GetString("SMTP_HOST")
+	if host == "" {
+		log.Warn("SMTP_HOST is not set, emails will only be logged")
+		return mail.NewLogMailer(log)
+	}
+
+	return mail.NewSMTPMailer(
+		host,
+		viper.GetInt("SMTP_PORT"),
+		viper.GetString("SMTP_USERNAME"),
+		viper.GetString("SMTP_PASSWORD"),
+		viper.GetString("SMTP_FROM"),
+	)
+}
@@ -24,6 +24,7 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local", username, password, host, port, database)
 
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		TranslateError: true,
 		Logger: logger.New(&logrusWriter{Logger: log}, logger.Config{
 			SlowThreshold:             time.Second * 5,
 			Colorful:                  false,
@@ -49,7 +50,31 @@ func NewDatabase(viper *viper.Viper, log *logrus.Logger) *gorm.DB {
 		entity.User{},
 		entity.Post{},
 		entity.Tag{},
+		entity.LoginThrottle{},
+		entity.EmailChange{},
+		entity.UsernameHistory{},
+		entity.AccountDeletion{},
+		entity.DataExport{},
+		entity.Comment{},
+		entity.Reaction{},
+		entity.PostReactionCount{},
+		entity.Bookmark{},
+		entity.ReadingList{},
+		entity.ReadingListItem{},
+		entity.UserFollow{},
+		entity.TagFollow{},
+		entity.Notification{},
+		entity.NotificationPreference{},
+		entity.Media{},
+		entity.MediaVariant{},
+		entity.PostImage{},
+		entity.Series{},
+		entity.SeriesPost{},
+		entity.PostAuthor{},
 	)
+
+	// Posts created before publishing was tracked were public from the start.
+	db.Model(&entity.Post{}).Where("published_at IS NULL").UpdateColumn("published_at", gorm.Expr("created_at"))
 	return db
 }
 
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"go-blog/internal/helper"
+)
+
+func NewPasswordPolicy(viper *viper.Viper) *helper.PasswordPolicy {
+	minLength := viper.GetInt("PASSWORD_MIN_LENGTH")
+	if minLength <= 0 {
+		minLength = 8
+	}
+
+	minEntropy := viper.GetFloat64("PASSWORD_MIN_ENTROPY")
+	if minEntropy <= 0 {
+		minEntropy = 40
+	}
+
+	return helper.NewPasswordPolicy(minLength, minEntropy, viper.GetString("PASSWORD_BREACHED_DIR"))
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/gateway/realtime"
+)
+
+// NewRealtimeHub starts the event hub on the in-process broker. With APP_PREFORK or several
+// instances, each process has its own hub and a shared realtime.Broker has to be plugged in here.
+func NewRealtimeHub(viper *viper.Viper, log *logrus.Logger) *realtime.Hub {
+	bufferSize := viper.GetInt("SSE_BUFFER_SIZE")
+	if bufferSize <= 0 {
+		bufferSize = 1000
+	}
+	clientBuffer := viper.GetInt("SSE_CLIENT_BUFFER")
+	if clientBuffer <= 0 {
+		clientBuffer = 64
+	}
+
+	if viper.GetBool("APP_PREFORK") {
+		log.Warn("APP_PREFORK is set, server-sent events only reach clients of the process they were published in")
+	}
+
+	hub := realtime.NewHub(log, realtime.NewLocalBroker(), bufferSize, clientBuffer)
+	if err := hub.Run(context.Background()); err != nil {
+		log.Fatalf("Failed to start realtime hub: %v", err)
+	}
+	return hub
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"context"
+	"time"
+)
+
+// RunEvery calls job every interval in the background for as long as the process runs.
+func RunEvery(interval time.Duration, job func(ctx context.Context)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			job(context.Background())
+		}
+	}()
+}
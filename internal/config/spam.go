@@ -0,0 +1,21 @@
+package config
+
+import (
+	"github.com/spf13/viper"
+	"go-blog/internal/helper"
+	"strings"
+)
+
+func NewSpamFilter(viper *viper.Viper) *helper.SpamFilter {
+	maxLinks := viper.GetInt("COMMENT_SPAM_MAX_LINKS")
+	if maxLinks <= 0 {
+		maxLinks = 2
+	}
+
+	var blocklist []string
+	if words := viper.GetString("COMMENT_SPAM_BLOCKLIST"); words != "" {
+		blocklist = strings.Split(words, ",")
+	}
+
+	return helper.NewSpamFilter(maxLinks, blocklist)
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/repository"
+	"go-blog/internal/usecase"
+	"gorm.io/gorm"
+	"io/fs"
+)
+
+// NewStaticSite wires what the export-static command needs, the server is not started.
+func NewStaticSite(db *gorm.DB, log *logrus.Logger, config *viper.Viper, theme fs.FS) *usecase.StaticSiteUseCase {
+	userRepository := repository.NewUserRepository(log)
+	postRepository := repository.NewPostRepository(log)
+	tagRepository := repository.NewTagRepository(log)
+	usernameHistoryRepository := repository.NewUsernameHistoryRepository(log)
+	seriesRepository := repository.NewSeriesRepository(log)
+
+	siteUseCase := usecase.NewSiteUseCase(db, log, config, postRepository, tagRepository, userRepository, usernameHistoryRepository, seriesRepository)
+	syndicationUseCase := usecase.NewSyndicationUseCase(db, log, config, postRepository, userRepository, tagRepository, seriesRepository, usernameHistoryRepository)
+	sitemapUseCase := usecase.NewSitemapUseCase(db, log, config, postRepository, tagRepository, userRepository, seriesRepository)
+
+	return usecase.NewStaticSiteUseCase(db, log, config, postRepository, tagRepository, userRepository, seriesRepository, siteUseCase, syndicationUseCase, sitemapUseCase, NewViewEngine(config, theme), theme)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/gateway/storage"
+	"path/filepath"
+	"strings"
+)
+
+// localMediaPath is where the app serves the files of the local storage.
+const localMediaPath = "/uploads"
+
+// NewStorage returns the S3 compatible storage when S3_BUCKET is set, otherwise the files are kept in
+// MEDIA_DIR and served by app.
+func NewStorage(viper *viper.Viper, log *logrus.Logger, app *fiber.App) storage.Storage {
+	if bucket := viper.GetString("S3_BUCKET"); bucket != "" {
+		region := viper.GetString("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return storage.NewS3Storage(
+			viper.GetString("S3_ENDPOINT"),
+			region,
+			bucket,
+			viper.GetString("S3_ACCESS_KEY"),
+			viper.GetString("S3_SECRET_KEY"),
+			viper.GetString("S3_PUBLIC_URL"),
+			viper.GetBool("S3_PATH_STYLE"),
+		)
+	}
+
+	dir := viper.GetString("MEDIA_DIR")
+	if dir == "" {
+		dir = filepath.Join("storage", "media")
+	}
+	log.Infof("S3_BUCKET is not set, uploads are stored in %s", dir)
+
+	// Keys are never reused, so the files can be cached for good. nosniff keeps browsers from
+	// guessing another type than the sniffed one the file was accepted as.
+	app.Static(localMediaPath, dir, fiber.Static{
+		MaxAge: 365 * 24 * 60 * 60,
+		ModifyResponse: func(ctx *fiber.Ctx) error {
+			ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+			return nil
+		},
+	})
+
+	return storage.NewLocalStorage(dir, strings.TrimRight(viper.GetString("APP_BASE_URL"), "/")+localMediaPath)
+}
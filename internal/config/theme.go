@@ -0,0 +1,38 @@
+package config
+
+import (
+	"github.com/gofiber/template/html/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/helper"
+	"go-blog/theme"
+	"io/fs"
+	"net/http"
+)
+
+// NewTheme opens THEME_DIR over the built-in default theme, without THEME_DIR the default theme is used as is.
+func NewTheme(viper *viper.Viper, log *logrus.Logger) fs.FS {
+	themeFS, err := theme.Open(viper.GetString("THEME_DIR"))
+	if err != nil {
+		log.Fatalf("Failed to open theme: %v", err)
+	}
+	return themeFS
+}
+
+// NewViewEngine loads the templates of the theme. In development they are parsed again on every render,
+// so template changes show up without a restart.
+func NewViewEngine(viper *viper.Viper, themeFS fs.FS) *html.Engine {
+	templates, err := fs.Sub(themeFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+
+	engine := html.NewFileSystem(http.FS(templates), ".html")
+	engine.AddFuncMap(helper.TemplateFuncs(viper.GetString("APP_BASE_URL")))
+	engine.Reload(isDevelopment(viper))
+	return engine
+}
+
+func isDevelopment(viper *viper.Viper) bool {
+	return viper.GetString("APP_ENV") == "development"
+}
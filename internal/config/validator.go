@@ -3,14 +3,20 @@ package config
 import (
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
+	"regexp"
 )
 
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
 func NewValidator() *validator.Validate {
 	v := validator.New()
 	if err := v.RegisterValidation("validateEmail", validateEmail); err != nil {
 		// Handle error if registration fails
 		logrus.Panic(err)
 	}
+	if err := v.RegisterValidation("validateUsername", validateUsername); err != nil {
+		logrus.Panic(err)
+	}
 	return v
 }
 
@@ -22,3 +28,12 @@ func validateEmail(fl validator.FieldLevel) bool {
 	// Validate email format
 	return validator.New().Var(email, "email") == nil
 }
+
+// validateUsername only allows characters that are safe to use as-is in author URLs.
+func validateUsername(fl validator.FieldLevel) bool {
+	username := fl.Field().String()
+	if username == "" {
+		return true
+	}
+	return usernamePattern.MatchString(username)
+}
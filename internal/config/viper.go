@@ -16,7 +16,7 @@ func NewViper() *viper.Viper {
 	err := config.ReadInConfig()
 
 	if err != nil {
-		fmt.Printf("Fatal error .env file: %w \n", err)
+		fmt.Printf("Fatal error .env file: %v \n", err)
 	}
 
 	return config
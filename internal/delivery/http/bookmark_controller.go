@@ -0,0 +1,75 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+)
+
+type BookmarkController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.BookmarkUseCase
+}
+
+func NewBookmarkController(logger *logrus.Logger, useCase *usecase.BookmarkUseCase) *BookmarkController {
+	return &BookmarkController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Add godoc
+// @Tags Bookmarks
+// @Summary Bookmark a post.
+// @Description API bookmark a post for later, list bookmarks with GET /api/posts?bookmarked=true.
+// @Security Bearer
+// @ID bookmark-post
+// @Router /api/posts/{slug}/bookmark [post]
+// @Param slug path string true "Post Slug"
+// @Produce json
+// @Success 200
+func (c *BookmarkController) Add(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.BookmarkRequest{
+		Slug:   ctx.Params("slug"),
+		UserId: user.ID,
+	}
+
+	response, err := c.UseCase.Add(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to bookmark post : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.BookmarkResponse]{Data: response})
+}
+
+// Remove godoc
+// @Tags Bookmarks
+// @Summary Remove a bookmark.
+// @Description API remove a post from the bookmarks of the logged in user.
+// @Security Bearer
+// @ID unbookmark-post
+// @Router /api/posts/{slug}/bookmark [delete]
+// @Param slug path string true "Post Slug"
+// @Produce json
+// @Success 200
+func (c *BookmarkController) Remove(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.BookmarkRequest{
+		Slug:   ctx.Params("slug"),
+		UserId: user.ID,
+	}
+
+	response, err := c.UseCase.Remove(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to remove bookmark : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.BookmarkResponse]{Data: response})
+}
@@ -0,0 +1,266 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+	"math"
+)
+
+type CommentController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.CommentUseCase
+}
+
+func NewCommentController(logger *logrus.Logger, useCase *usecase.CommentUseCase) *CommentController {
+	return &CommentController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// List godoc
+// @Tags Comments
+// @Summary Get comments of a post.
+// @Description API get comments of a post, as a tree of threads (paginated by top level comment) or as a flat list.
+// @ID get-comments
+// @Router /api/post/{slug}/comments [get]
+// @Param slug path string true "Post Slug"
+// @Param mode query string false "tree or flat" default(tree)
+// @Param page query int false "Page Number" default(1)
+// @Param size query int false "Size" default(20)
+// @Produce json
+// @Success 200
+func (c *CommentController) List(ctx *fiber.Ctx) error {
+	request := &model.SearchCommentRequest{
+		Slug: ctx.Params("slug"),
+		Mode: ctx.Query("mode", "tree"),
+		Paginate: model.Pagination{
+			Page: ctx.QueryInt("page", 1),
+			Size: ctx.QueryInt("size", 20),
+		},
+	}
+
+	response, total, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to load comments : %+v", err)
+		return err
+	}
+
+	paging := &model.PageMetadata{
+		Page:      request.Paginate.Page,
+		Size:      request.Paginate.Size,
+		TotalItem: total,
+		TotalPage: int64(math.Ceil(float64(total) / float64(request.Paginate.Size))),
+	}
+	return ctx.JSON(model.WebResponse[[]*model.CommentResponse]{Data: response, Paging: paging})
+}
+
+// Create godoc
+// @Tags Comments
+// @Summary Comment on a post.
+// @Description API comment on a post, set parent_id to reply to another comment.
+// @Security Bearer
+// @ID create-comment
+// @Router /api/post/{slug}/comments [post]
+// @Param slug path string true "Post Slug"
+// @Param _ body model.CreateCommentRequest true "Request create comment"
+// @Accept json
+// @Produce json
+// @Success 201
+func (c *CommentController) Create(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := new(model.CreateCommentRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.Slug = ctx.Params("slug")
+	request.UserID = user.ID
+
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create comment : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.CommentResponse]{Data: response})
+}
+
+// Update godoc
+// @Tags Comments
+// @Summary Edit a comment.
+// @Description API edit your own comment, only allowed for a short time after posting it.
+// @Security Bearer
+// @ID update-comment
+// @Router /api/comments/{commentId} [patch]
+// @Param commentId path int true "Comment ID"
+// @Param _ body model.UpdateCommentRequest true "Request update comment"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *CommentController) Update(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	id, err := ctx.ParamsInt("commentId")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment id")
+	}
+
+	request := new(model.UpdateCommentRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.ID = uint(id)
+	request.UserID = user.ID
+
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update comment : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.CommentResponse]{Data: response})
+}
+
+// Delete godoc
+// @Tags Comments
+// @Summary Delete a comment.
+// @Description API delete your own comment, replies are kept.
+// @Security Bearer
+// @ID delete-comment
+// @Router /api/comments/{commentId} [delete]
+// @Param commentId path int true "Comment ID"
+// @Produce json
+// @Success 200
+func (c *CommentController) Delete(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	id, err := ctx.ParamsInt("commentId")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment id")
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), user.ID, uint(id)); err != nil {
+		c.Log.Warnf("Failed to delete comment : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[string]{Data: "Successfully delete comment"})
+}
+
+// ListModeration godoc
+// @Tags Comments
+// @Summary Get the comment moderation queue.
+// @Description API get comments by moderation status on all posts, restricted for admin only.
+// @Security BasicAuth
+// @ID get-moderation-comments
+// @Router /api/admin/comments [get]
+// @Param status query string false "pending, approved, rejected or spam" default(pending)
+// @Param page query int false "Page Number" default(1)
+// @Param size query int false "Size" default(20)
+// @Produce json
+// @Success 200
+func (c *CommentController) ListModeration(ctx *fiber.Ctx) error {
+	return c.listModeration(ctx, "")
+}
+
+// ListOwnModeration godoc
+// @Tags Comments
+// @Summary Get the comment moderation queue of your posts.
+// @Description API get comments by moderation status on the posts of the user that currently logged in.
+// @Security Bearer
+// @ID get-own-moderation-comments
+// @Router /api/users/me/comments [get]
+// @Param status query string false "pending, approved, rejected or spam" default(pending)
+// @Param page query int false "Page Number" default(1)
+// @Param size query int false "Size" default(20)
+// @Produce json
+// @Success 200
+func (c *CommentController) ListOwnModeration(ctx *fiber.Ctx) error {
+	return c.listModeration(ctx, middleware.GetUser(ctx).ID)
+}
+
+// Moderate godoc
+// @Tags Comments
+// @Summary Moderate a comment.
+// @Description API approve, reject or mark a comment as spam, restricted for admin only.
+// @Security BasicAuth
+// @ID moderate-comment
+// @Router /api/admin/comments/{commentId} [patch]
+// @Param commentId path int true "Comment ID"
+// @Param _ body model.ModerateCommentRequest true "Request moderate comment"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *CommentController) Moderate(ctx *fiber.Ctx) error {
+	return c.moderate(ctx, "")
+}
+
+// ModerateOwn godoc
+// @Tags Comments
+// @Summary Moderate a comment on your post.
+// @Description API approve, reject or mark a comment as spam on a post of the user that currently logged in.
+// @Security Bearer
+// @ID moderate-own-comment
+// @Router /api/comments/{commentId}/moderation [patch]
+// @Param commentId path int true "Comment ID"
+// @Param _ body model.ModerateCommentRequest true "Request moderate comment"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *CommentController) ModerateOwn(ctx *fiber.Ctx) error {
+	return c.moderate(ctx, middleware.GetUser(ctx).ID)
+}
+
+func (c *CommentController) listModeration(ctx *fiber.Ctx, postOwnerId string) error {
+	request := &model.SearchModerationRequest{
+		Status:      ctx.Query("status", "pending"),
+		PostOwnerID: postOwnerId,
+		Paginate: model.Pagination{
+			Page: ctx.QueryInt("page", 1),
+			Size: ctx.QueryInt("size", 20),
+		},
+	}
+
+	response, total, err := c.UseCase.ListModeration(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to load moderation comments : %+v", err)
+		return err
+	}
+
+	paging := &model.PageMetadata{
+		Page:      request.Paginate.Page,
+		Size:      request.Paginate.Size,
+		TotalItem: total,
+		TotalPage: int64(math.Ceil(float64(total) / float64(request.Paginate.Size))),
+	}
+	return ctx.JSON(model.WebResponse[[]*model.CommentResponse]{Data: response, Paging: paging})
+}
+
+func (c *CommentController) moderate(ctx *fiber.Ctx, moderatorId string) error {
+	id, err := ctx.ParamsInt("commentId")
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid comment id")
+	}
+
+	request := new(model.ModerateCommentRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.ID = uint(id)
+	request.ModeratorID = moderatorId
+
+	response, err := c.UseCase.Moderate(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to moderate comment : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.CommentResponse]{Data: response})
+}
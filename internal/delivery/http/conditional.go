@@ -0,0 +1,50 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// sendCacheable sends body with an ETag of its content and, when lastModified is set, a Last-Modified
+// header, and answers 304 Not Modified when the conditional request headers still match.
+func sendCacheable(ctx *fiber.Ctx, contentType string, body []byte, lastModified *time.Time) error {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	ctx.Set(fiber.HeaderETag, etag)
+	ctx.Set(fiber.HeaderCacheControl, "public, no-cache")
+	if lastModified != nil {
+		ctx.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(ctx, etag, lastModified) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	return ctx.Send(body)
+}
+
+// notModified follows RFC 9110, If-None-Match wins over If-Modified-Since. fiber.Ctx.Fresh is not
+// used because it treats a lone If-Modified-Since as a match whatever the date.
+func notModified(ctx *fiber.Ctx, etag string, lastModified *time.Time) bool {
+	if match := ctx.Get(fiber.HeaderIfNoneMatch); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := ctx.Get(fiber.HeaderIfModifiedSince); since != "" && lastModified != nil {
+		sinceTime, err := http.ParseTime(since)
+		return err == nil && !lastModified.Truncate(time.Second).After(sinceTime)
+	}
+	return false
+}
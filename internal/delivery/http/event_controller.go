@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/gateway/realtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type EventController struct {
+	Log       *logrus.Logger
+	Hub       *realtime.Hub
+	Heartbeat time.Duration
+}
+
+func NewEventController(logger *logrus.Logger, hub *realtime.Hub, heartbeat time.Duration) *EventController {
+	return &EventController{
+		Log:       logger,
+		Hub:       hub,
+		Heartbeat: heartbeat,
+	}
+}
+
+// Stream godoc
+// @Tags Events
+// @Summary Stream real-time events.
+// @Description Server-Sent Events stream. Topics are posts (new posts), post:{slug} (changes on a post) and me (changes on the own account), default posts,me.
+// @Description Send Last-Event-ID (or the last_event_id query) to resume after a reconnect. The token can be sent as access_token query for EventSource.
+// @Security Bearer
+// @ID stream-events
+// @Router /api/events [get]
+// @Param topics query string false "Comma separated topics"
+// @Produce text/event-stream
+// @Success 200
+func (c *EventController) Stream(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	topics, err := parseTopics(ctx.Query("topics", "posts,me"), user.ID)
+	if err != nil {
+		return err
+	}
+
+	lastEventId, _ := strconv.ParseUint(ctx.Get("Last-Event-ID", ctx.Query("last_event_id")), 10, 64)
+	client, replay := c.Hub.Subscribe(topics, lastEventId)
+
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no")
+
+	ctx.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer c.Hub.Unsubscribe(client)
+
+		heartbeat := time.NewTicker(c.Heartbeat)
+		defer heartbeat.Stop()
+
+		fmt.Fprintf(w, "retry: %d\n\n", 3000)
+		for _, event := range replay {
+			writeEvent(w, event)
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-client.Events:
+				if !ok {
+					return
+				}
+				writeEvent(w, event)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+			// Flush fails once the client went away, which ends the stream.
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}))
+
+	return nil
+}
+
+// parseTopics checks the requested topics, me stands for the account of the caller.
+func parseTopics(query string, userId string) ([]string, error) {
+	var topics []string
+	for _, topic := range strings.Split(query, ",") {
+		topic = strings.TrimSpace(topic)
+		switch {
+		case topic == "":
+			continue
+		case topic == realtime.TopicPosts:
+			topics = append(topics, topic)
+		case topic == "me":
+			topics = append(topics, realtime.TopicUser(userId))
+		case strings.HasPrefix(topic, "post:") && len(topic) > len("post:"):
+			topics = append(topics, topic)
+		default:
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Unknown topic "+topic)
+		}
+	}
+	if len(topics) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "No topic to subscribe to")
+	}
+	return topics, nil
+}
+
+func writeEvent(w *bufio.Writer, event *realtime.Event) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+)
+
+type ExportController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ExportUseCase
+}
+
+func NewExportController(logger *logrus.Logger, useCase *usecase.ExportUseCase) *ExportController {
+	return &ExportController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Create godoc
+// @Tags Users
+// @Summary Request a data export.
+// @Description API for request a ZIP archive of your profile, account metadata and posts. The archive is built in the background, poll the export until it is ready.
+// @ID create-data-export
+// @Security Bearer
+// @Router /api/users/me/exports [post]
+// @Produce json
+// @Success 202
+func (c *ExportController) Create(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.Create(ctx.UserContext(), auth.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to create data export : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(model.WebResponse[*model.DataExportResponse]{Data: response})
+}
+
+// Get godoc
+// @Tags Users
+// @Summary Get a data export.
+// @Description API for get the status of a data export, download_url is set once it is ready and expires with the export.
+// @ID get-data-export
+// @Security Bearer
+// @Router /api/users/me/exports/{exportId} [get]
+// @Param exportId path string true "Export ID"
+// @Produce json
+// @Success 200
+func (c *ExportController) Get(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.Get(ctx.UserContext(), auth.ID, ctx.Params("exportId"))
+	if err != nil {
+		c.Log.Warnf("Failed to get data export : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.DataExportResponse]{Data: response})
+}
+
+// Download godoc
+// @Tags Users
+// @Summary Download a data export.
+// @Description API for download a data export archive through its signed link.
+// @ID download-data-export
+// @Router /api/exports/{exportId}/download [get]
+// @Param exportId path string true "Export ID"
+// @Param expires query int true "Link expiry as unix time"
+// @Param signature query string true "Link signature"
+// @Produce application/zip
+// @Success 200
+func (c *ExportController) Download(ctx *fiber.Ctx) error {
+	request := &model.DownloadDataExportRequest{
+		ID:        ctx.Params("exportId"),
+		Expires:   int64(ctx.QueryInt("expires")),
+		Signature: ctx.Query("signature"),
+	}
+
+	path, name, err := c.UseCase.Download(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to download data export : %+v", err)
+		return err
+	}
+
+	return ctx.Download(path, name)
+}
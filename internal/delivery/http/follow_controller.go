@@ -0,0 +1,150 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+)
+
+type FollowController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.FollowUseCase
+}
+
+func NewFollowController(logger *logrus.Logger, useCase *usecase.FollowUseCase) *FollowController {
+	return &FollowController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// FollowUser godoc
+// @Tags Follows
+// @Summary Follow an author.
+// @Description API follow an author, their posts then show up in the feed.
+// @Security Bearer
+// @ID follow-user
+// @Router /api/users/{username}/follow [post]
+// @Param username path string true "Username"
+// @Produce json
+// @Success 200
+func (c *FollowController) FollowUser(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.FollowUserRequest{
+		UserId:   user.ID,
+		Username: ctx.Params("username"),
+	}
+
+	response, err := c.UseCase.FollowUser(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to follow user : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.FollowResponse]{Data: response})
+}
+
+// UnfollowUser godoc
+// @Tags Follows
+// @Summary Unfollow an author.
+// @Description API stop following an author.
+// @Security Bearer
+// @ID unfollow-user
+// @Router /api/users/{username}/follow [delete]
+// @Param username path string true "Username"
+// @Produce json
+// @Success 200
+func (c *FollowController) UnfollowUser(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.FollowUserRequest{
+		UserId:   user.ID,
+		Username: ctx.Params("username"),
+	}
+
+	response, err := c.UseCase.UnfollowUser(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to unfollow user : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.FollowResponse]{Data: response})
+}
+
+// FollowTag godoc
+// @Tags Follows
+// @Summary Follow a tag.
+// @Description API follow a tag, posts with it then show up in the feed.
+// @Security Bearer
+// @ID follow-tag
+// @Router /api/tags/{slug}/follow [post]
+// @Param slug path string true "Tag Slug"
+// @Produce json
+// @Success 200
+func (c *FollowController) FollowTag(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.FollowTagRequest{
+		UserId: user.ID,
+		Slug:   ctx.Params("slug"),
+	}
+
+	response, err := c.UseCase.FollowTag(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to follow tag : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.FollowResponse]{Data: response})
+}
+
+// UnfollowTag godoc
+// @Tags Follows
+// @Summary Unfollow a tag.
+// @Description API stop following a tag.
+// @Security Bearer
+// @ID unfollow-tag
+// @Router /api/tags/{slug}/follow [delete]
+// @Param slug path string true "Tag Slug"
+// @Produce json
+// @Success 200
+func (c *FollowController) UnfollowTag(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.FollowTagRequest{
+		UserId: user.ID,
+		Slug:   ctx.Params("slug"),
+	}
+
+	response, err := c.UseCase.UnfollowTag(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to unfollow tag : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.FollowResponse]{Data: response})
+}
+
+// ListFollowing godoc
+// @Tags Follows
+// @Summary Get followed authors and tags.
+// @Description API get the authors and tags the logged in user follows.
+// @Security Bearer
+// @ID get-following
+// @Router /api/users/me/following [get]
+// @Produce json
+// @Success 200
+func (c *FollowController) ListFollowing(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.ListFollowing(ctx.UserContext(), user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to load following : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.FollowingResponse]{Data: response})
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+	"math"
+)
+
+type MediaController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.MediaUseCase
+}
+
+func NewMediaController(logger *logrus.Logger, useCase *usecase.MediaUseCase) *MediaController {
+	return &MediaController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Upload godoc
+// @Tags Media
+// @Summary Upload an image.
+// @Description API for upload a JPEG, PNG, GIF or WebP image as the file field of a multipart form. The type is detected from the content, the size is limited by MEDIA_MAX_SIZE_MB.
+// @ID upload-media
+// @Security Bearer
+// @Router /api/media [post]
+// @Accept multipart/form-data
+// @Param file formData file true "Image"
+// @Produce json
+// @Success 201
+func (c *MediaController) Upload(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		c.Log.Warnf("Failed to read upload : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, "The file field is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.Log.Warnf("Failed to open upload : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	defer file.Close()
+
+	request := &model.UploadMediaRequest{
+		UserId:   auth.ID,
+		FileName: header.Filename,
+		Size:     header.Size,
+		File:     file,
+	}
+
+	response, err := c.UseCase.Upload(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to upload media : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.MediaResponse]{Data: response})
+}
+
+// List godoc
+// @Tags Media
+// @Summary List your uploads.
+// @Description API for list the uploads of the logged in user, newest first.
+// @ID list-media
+// @Security Bearer
+// @Router /api/users/me/media [get]
+// @Param page query int false "Page" default(1)
+// @Param size query int false "Page size" default(20)
+// @Produce json
+// @Success 200
+func (c *MediaController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	request := &model.SearchMediaRequest{
+		UserId: auth.ID,
+		Paginate: model.Pagination{
+			Page: ctx.QueryInt("page", 1),
+			Size: ctx.QueryInt("size", 20),
+		},
+	}
+
+	response, total, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to list media : %+v", err)
+		return err
+	}
+
+	paging := &model.PageMetadata{
+		Page:      request.Paginate.Page,
+		Size:      request.Paginate.Size,
+		TotalItem: total,
+		TotalPage: int64(math.Ceil(float64(total) / float64(request.Paginate.Size))),
+	}
+	return ctx.JSON(model.WebResponse[[]model.MediaResponse]{Data: response, Paging: paging})
+}
+
+// Delete godoc
+// @Tags Media
+// @Summary Delete an upload.
+// @Description API for delete one of your uploads, an image still used as a post cover or in a gallery cannot be deleted.
+// @ID delete-media
+// @Security Bearer
+// @Router /api/media/{mediaId} [delete]
+// @Param mediaId path string true "Media ID"
+// @Produce json
+// @Success 200
+func (c *MediaController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	if err := c.UseCase.Delete(ctx.UserContext(), auth.ID, ctx.Params("mediaId")); err != nil {
+		c.Log.Warnf("Failed to delete media : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[string]{Data: "Successfully delete media"})
+}
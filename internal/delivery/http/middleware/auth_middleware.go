@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
@@ -26,6 +27,32 @@ func NewMiddleware(v *viper.Viper, l *logrus.Logger) *Middleware {
 }
 
 func (m *Middleware) ValidateJWT(ctx *fiber.Ctx) error {
+	auth, err := m.parseJWT(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx.Locals("auth", auth)
+	return ctx.Next()
+}
+
+// OptionalJWT authenticates the caller when a valid bearer token is sent. Anonymous requests go
+// through, and so do those with an expired or invalid token, as anonymous ones.
+func (m *Middleware) OptionalJWT(ctx *fiber.Ctx) error {
+	if ctx.Get("Authorization") == "" {
+		return ctx.Next()
+	}
+	auth, err := m.parseJWT(ctx)
+	if err != nil {
+		m.Log.Debugf("Ignoring token on optional auth route : %+v", err)
+		return ctx.Next()
+	}
+
+	ctx.Locals("auth", auth)
+	return ctx.Next()
+}
+
+func (m *Middleware) parseJWT(ctx *fiber.Ctx) (*model.Auth, error) {
 	var token string
 	authorization := ctx.Get("Authorization")
 
@@ -34,7 +61,7 @@ func (m *Middleware) ValidateJWT(ctx *fiber.Ctx) error {
 	}
 
 	if token == "" {
-		return fiber.NewError(fiber.StatusUnauthorized, "Token Empty")
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Token Empty")
 	}
 
 	tokenByte, err := jwt.Parse(token, func(jwtToken *jwt.Token) (interface{}, error) {
@@ -45,30 +72,53 @@ func (m *Middleware) ValidateJWT(ctx *fiber.Ctx) error {
 		return []byte(m.Config.GetString("JWT_SECRET")), nil
 	})
 	if err != nil {
-		return fiber.NewError(fiber.StatusUnauthorized, "A "+err.Error())
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "A "+err.Error())
 
 	}
 
 	claims, ok := tokenByte.Claims.(jwt.MapClaims)
 	if !ok || !tokenByte.Valid {
-		return fiber.NewError(fiber.StatusUnauthorized, "B "+err.Error())
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "B Invalid token")
 	}
 
-	subClaims := claims["auth"].(string)
+	subClaims, ok := claims["auth"].(string)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "B Invalid token")
+	}
 	auth := new(model.Auth)
 
 	if err := json.Unmarshal([]byte(subClaims), auth); err != nil {
-		return fiber.ErrInternalServerError
+		return nil, fiber.ErrInternalServerError
 	}
 
-	ctx.Locals("auth", auth)
-	return ctx.Next()
+	return auth, nil
 }
 
+// ValidateJWTOrQuery also accepts the token in the access_token query parameter, for clients
+// like the browser EventSource that cannot set headers.
+func (m *Middleware) ValidateJWTOrQuery(ctx *fiber.Ctx) error {
+	if token := ctx.Query("access_token"); token != "" && ctx.Get("Authorization") == "" {
+		ctx.Request().Header.Set("Authorization", "Bearer "+token)
+	}
+	return m.ValidateJWT(ctx)
+}
+
+// BasicAuth guards the admin routes. It fails closed, every request is refused while
+// BASIC_AUTH_USERNAME or BASIC_AUTH_PASSWORD is not set.
 func (m *Middleware) BasicAuth(c *fiber.Ctx) error {
+	username := m.Config.GetString("BASIC_AUTH_USERNAME")
+	password := m.Config.GetString("BASIC_AUTH_PASSWORD")
+	if username == "" || password == "" {
+		m.Log.Warn("Admin route refused, BASIC_AUTH_USERNAME and BASIC_AUTH_PASSWORD must be set")
+		return fiber.ErrForbidden
+	}
+
 	config := basicauth.Config{
-		Users: map[string]string{
-			m.Config.GetString("BASIC_AUTH_USERNAME"): m.Config.GetString("BASIC_AUTH_PASSWORD"),
+		Authorizer: func(user, pass string) bool {
+			// Both are compared in full so the time taken does not tell which one is wrong.
+			userMatch := subtle.ConstantTimeCompare([]byte(user), []byte(username))
+			passMatch := subtle.ConstantTimeCompare([]byte(pass), []byte(password))
+			return userMatch&passMatch == 1
 		},
 		Unauthorized: func(ctx *fiber.Ctx) error {
 			return fiber.ErrForbidden
@@ -81,6 +131,14 @@ func GetUser(ctx *fiber.Ctx) *model.Auth {
 	return ctx.Locals("auth").(*model.Auth)
 }
 
+// GetViewerId returns the id of the caller on routes behind OptionalJWT, empty for anonymous requests.
+func GetViewerId(ctx *fiber.Ctx) string {
+	if auth, ok := ctx.Locals("auth").(*model.Auth); ok {
+		return auth.ID
+	}
+	return ""
+}
+
 func (m *Middleware) GenerateToken(auth *model.Auth) (string, error) {
 	jwtSecret := m.Config.GetString("JWT_SECRET")
 	authJSON, err := json.Marshal(auth)
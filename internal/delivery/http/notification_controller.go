@@ -0,0 +1,160 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+	"math"
+)
+
+type NotificationController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.NotificationUseCase
+}
+
+func NewNotificationController(logger *logrus.Logger, useCase *usecase.NotificationUseCase) *NotificationController {
+	return &NotificationController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// List godoc
+// @Tags Notifications
+// @Summary Get notifications.
+// @Description API get the notifications of the logged in user, newest first, with the number of unread ones.
+// @Security Bearer
+// @ID get-notifications
+// @Router /api/notifications [get]
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page Number" default(1)
+// @Param size query int false "Size" default(20)
+// @Produce json
+// @Success 200
+func (c *NotificationController) List(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.SearchNotificationRequest{
+		UserId: user.ID,
+		Unread: ctx.QueryBool("unread"),
+		Paginate: model.Pagination{
+			Page: ctx.QueryInt("page", 1),
+			Size: ctx.QueryInt("size", 20),
+		},
+	}
+
+	response, total, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to load notifications : %+v", err)
+		return err
+	}
+
+	paging := &model.PageMetadata{
+		Page:      request.Paginate.Page,
+		Size:      request.Paginate.Size,
+		TotalItem: total,
+		TotalPage: int64(math.Ceil(float64(total) / float64(request.Paginate.Size))),
+	}
+	return ctx.JSON(model.WebResponse[*model.NotificationListResponse]{Data: response, Paging: paging})
+}
+
+// MarkRead godoc
+// @Tags Notifications
+// @Summary Mark a notification as read.
+// @Description API mark a notification of the logged in user as read.
+// @Security Bearer
+// @ID read-notification
+// @Router /api/notifications/{notificationId}/read [post]
+// @Param notificationId path int true "Notification ID"
+// @Produce json
+// @Success 200
+func (c *NotificationController) MarkRead(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	id, err := ctx.ParamsInt("notificationId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	response, err := c.UseCase.MarkRead(ctx.UserContext(), user.ID, uint(id))
+	if err != nil {
+		c.Log.Warnf("Failed to mark notification read : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.NotificationResponse]{Data: response})
+}
+
+// MarkAllRead godoc
+// @Tags Notifications
+// @Summary Mark all notifications as read.
+// @Description API mark every unread notification of the logged in user as read.
+// @Security Bearer
+// @ID read-all-notifications
+// @Router /api/notifications/read [post]
+// @Produce json
+// @Success 200
+func (c *NotificationController) MarkAllRead(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.MarkAllRead(ctx.UserContext(), user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to mark notifications read : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.MarkNotificationsReadResponse]{Data: response})
+}
+
+// GetPreferences godoc
+// @Tags Notifications
+// @Summary Get notification preferences.
+// @Description API get, per notification type, whether it is enabled and sent in the email digest.
+// @Security Bearer
+// @ID get-notification-preferences
+// @Router /api/notifications/preferences [get]
+// @Produce json
+// @Success 200
+func (c *NotificationController) GetPreferences(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.GetPreferences(ctx.UserContext(), user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to load notification preferences : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]*model.NotificationPreferenceResponse]{Data: response})
+}
+
+// UpdatePreferences godoc
+// @Tags Notifications
+// @Summary Update notification preferences.
+// @Description API enable or disable notification types and choose which ones go in the email digest.
+// @Security Bearer
+// @ID update-notification-preferences
+// @Router /api/notifications/preferences [patch]
+// @Param _ body model.UpdateNotificationPreferencesRequest true "Request update notification preferences"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *NotificationController) UpdatePreferences(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := new(model.UpdateNotificationPreferencesRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.UserId = user.ID
+
+	response, err := c.UseCase.UpdatePreferences(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update notification preferences : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]*model.NotificationPreferenceResponse]{Data: response})
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+)
+
+type PostAuthorController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.PostAuthorUseCase
+}
+
+func NewPostAuthorController(logger *logrus.Logger, useCase *usecase.PostAuthorUseCase) *PostAuthorController {
+	return &PostAuthorController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// List godoc
+// @Tags Post Authors
+// @Summary Get the contributors of a post.
+// @Description API get the primary author and contributors of a post with the pending invitations. Only the people credited or invited on the post may see them.
+// @Security Bearer
+// @ID get-post-authors
+// @Router /api/posts/{slug}/authors [get]
+// @Param slug path string true "Post Slug"
+// @Produce json
+// @Success 200
+func (c *PostAuthorController) List(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.PostAuthorRequest{
+		Slug:   ctx.Params("slug"),
+		UserId: user.ID,
+	}
+
+	response, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to load post authors : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]model.PostAuthorResponse]{Data: response})
+}
+
+// ListInvitations godoc
+// @Tags Post Authors
+// @Summary Get own invitations.
+// @Description API get the invitations to be credited on a post the logged in user did not answer yet.
+// @Security Bearer
+// @ID get-own-post-invitations
+// @Router /api/users/me/invitations [get]
+// @Produce json
+// @Success 200
+func (c *PostAuthorController) ListInvitations(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.ListInvitations(ctx.UserContext(), user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to load invitations : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]*model.PostInvitationResponse]{Data: response})
+}
+
+// Invite godoc
+// @Tags Post Authors
+// @Summary Invite a contributor.
+// @Description API invite a user to be credited on a post as author, editor or reviewer. Only the primary author can invite, authors and editors may edit the post once they accept.
+// @Security Bearer
+// @ID invite-post-author
+// @Router /api/posts/{slug}/authors [post]
+// @Param slug path string true "Post Slug"
+// @Param _ body model.InvitePostAuthorRequest true "Request invite contributor"
+// @Accept json
+// @Produce json
+// @Success 201
+// @Success 409 "User is already credited or invited"
+func (c *PostAuthorController) Invite(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := new(model.InvitePostAuthorRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.Slug = ctx.Params("slug")
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Invite(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to invite post author : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.PostAuthorResponse]{Data: response})
+}
+
+// Accept godoc
+// @Tags Post Authors
+// @Summary Accept an invitation.
+// @Description API accept the invitation of the logged in user to be credited on a post.
+// @Security Bearer
+// @ID accept-post-invitation
+// @Router /api/posts/{slug}/authors/accept [post]
+// @Param slug path string true "Post Slug"
+// @Produce json
+// @Success 200
+func (c *PostAuthorController) Accept(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.PostAuthorRequest{
+		Slug:   ctx.Params("slug"),
+		UserId: user.ID,
+	}
+
+	response, err := c.UseCase.Accept(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to accept invitation : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.PostAuthorResponse]{Data: response})
+}
+
+// Remove godoc
+// @Tags Post Authors
+// @Summary Remove a contributor.
+// @Description API end the credit or invitation of a user on a post. The primary author can remove anyone, contributors only themselves, which also declines an invitation.
+// @Security Bearer
+// @ID remove-post-author
+// @Router /api/posts/{slug}/authors/{username} [delete]
+// @Param slug path string true "Post Slug"
+// @Param username path string true "Username"
+// @Produce json
+// @Success 200
+func (c *PostAuthorController) Remove(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.PostAuthorRequest{
+		Slug:     ctx.Params("slug"),
+		UserId:   user.ID,
+		Username: ctx.Params("username"),
+	}
+
+	if err := c.UseCase.Remove(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to remove post author : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[string]{Data: "Successfully remove post author"})
+}
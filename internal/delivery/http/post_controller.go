@@ -52,6 +52,38 @@ func (c *PostController) CreatePost(ctx *fiber.Ctx) error {
 
 }
 
+// Update godoc
+// @Tags Posts
+// @Summary Update a post.
+// @Description API edit a post, open to its primary author and to the contributors credited as author or editor. Fields left empty are unchanged and the slug is kept.
+// @Security Bearer
+// @ID update-post
+// @Router /api/posts/{slug} [patch]
+// @Param slug path string true "Post Slug"
+// @Param _ body model.UpdatePostRequest true "Request update post"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *PostController) Update(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := new(model.UpdatePostRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.Slug = ctx.Params("slug")
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update post : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.PostResponse]{Data: response})
+}
+
 // List godoc
 // @Tags Posts
 // @Summary Get all posts.
@@ -61,6 +93,7 @@ func (c *PostController) CreatePost(ctx *fiber.Ctx) error {
 // @Param title query string false "Title"
 // @Param sort query string false "Sort"
 // @Param tags query []string false "Tags"
+// @Param bookmarked query bool false "Only posts bookmarked by the logged in user"
 // @Param page query int false "Page Number" default(1)
 // @Param size query int false "Size" default(10)
 // @Accept json
@@ -68,9 +101,11 @@ func (c *PostController) CreatePost(ctx *fiber.Ctx) error {
 // @Success 200
 func (c *PostController) List(ctx *fiber.Ctx) error {
 	request := &model.SearchPostRequest{
-		Title: ctx.Query("title", ""),
-		Tags:  strings.Split(ctx.Query("tags"), ","),
-		Sort:  ctx.Query("sort", ""),
+		Title:      ctx.Query("title", ""),
+		Tags:       strings.Split(ctx.Query("tags"), ","),
+		Sort:       ctx.Query("sort", ""),
+		ViewerId:   middleware.GetViewerId(ctx),
+		Bookmarked: ctx.QueryBool("bookmarked"),
 		Paginate: model.Pagination{
 			Page: ctx.QueryInt("page", 1),
 			Size: ctx.QueryInt("size", 10),
@@ -94,7 +129,7 @@ func (c *PostController) List(ctx *fiber.Ctx) error {
 // ListByUser godoc
 // @Tags Posts
 // @Summary Get all posts by specific user.
-// @Description API get all posts by specific user.
+// @Description API get all posts by specific user, including the ones they are credited on as a contributor.
 // @ID get-posts-by-user
 // @Router /api/posts/{username} [get]
 // @Param username path string true "Username"
@@ -105,12 +140,14 @@ func (c *PostController) List(ctx *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Success 200
+// @Success 301 "Username changed, redirects to the new author URL"
 func (c *PostController) ListByUser(ctx *fiber.Ctx) error {
 	request := &model.SearchPostRequest{
 		Username: ctx.Params("username", ""),
 		Sort:     ctx.Query("sort", ""),
 		Title:    ctx.Query("title", ""),
 		//Tag:      ctx.Query("tag", ""),
+		ViewerId: middleware.GetViewerId(ctx),
 		Paginate: model.Pagination{
 			Page: ctx.QueryInt("page", 1),
 			Size: ctx.QueryInt("size", 10),
@@ -118,6 +155,9 @@ func (c *PostController) ListByUser(ctx *fiber.Ctx) error {
 	}
 	response, total, err := c.UseCase.List(ctx.UserContext(), request)
 	if err != nil {
+		if ok, err := redirectMovedUsername(ctx, err, "/posts/"); ok {
+			return err
+		}
 		c.Log.Warnf("Failed to load posts: %+v", err)
 		return err
 	}
@@ -135,7 +175,7 @@ func (c *PostController) ListByUser(ctx *fiber.Ctx) error {
 // FindBySlug godoc
 // @Tags Posts
 // @Summary Get posts by post slug.
-// @Description API get post detail by slug.
+// @Description API get post detail by slug, meta holds the SEO and social tags for the page head.
 // @ID posts-by-slug
 // @Router /api/post/{slug} [get]
 // @Accept json
@@ -145,10 +185,43 @@ func (c *PostController) ListByUser(ctx *fiber.Ctx) error {
 func (c *PostController) FindBySlug(ctx *fiber.Ctx) error {
 	slug := ctx.Params("slug")
 
-	post, err := c.UseCase.GetBySlug(ctx.UserContext(), slug)
+	post, err := c.UseCase.GetBySlug(ctx.UserContext(), slug, middleware.GetViewerId(ctx))
 	if err != nil {
 		c.Log.Warnf("Failed to load post : %+v", err)
 		return err
 	}
 	return ctx.JSON(model.WebResponse[*model.PostResponse]{Data: post})
 }
+
+// Feed godoc
+// @Tags Posts
+// @Summary Get the personal feed.
+// @Description API get the published posts of the followed authors and tags, newest first. Pass cursor.next_cursor as cursor to get the next page.
+// @Security Bearer
+// @ID get-feed
+// @Router /api/feed [get]
+// @Param cursor query string false "Cursor"
+// @Param size query int false "Size" default(10)
+// @Produce json
+// @Success 200
+func (c *PostController) Feed(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.FeedRequest{
+		UserId: user.ID,
+		Cursor: ctx.Query("cursor"),
+		Size:   ctx.QueryInt("size", 10),
+	}
+
+	response, nextCursor, err := c.UseCase.Feed(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to load feed : %+v", err)
+		return err
+	}
+
+	cursor := &model.CursorMetadata{
+		Size:       request.Size,
+		NextCursor: nextCursor,
+	}
+	return ctx.JSON(model.WebResponse[[]model.PostResponse]{Data: response, Cursor: cursor})
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+)
+
+type ReactionController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ReactionUseCase
+}
+
+func NewReactionController(logger *logrus.Logger, useCase *usecase.ReactionUseCase) *ReactionController {
+	return &ReactionController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// React godoc
+// @Tags Reactions
+// @Summary React to a post.
+// @Description API react to a post with one of like, love, clap, insightful or laugh. A user keeps at most one reaction of each type.
+// @Security Bearer
+// @ID react-post
+// @Router /api/posts/{slug}/reactions [post]
+// @Param slug path string true "Post Slug"
+// @Param _ body model.ReactionRequest true "Request react to a post"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *ReactionController) React(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := new(model.ReactionRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.Slug = ctx.Params("slug")
+	request.UserId = user.ID
+
+	response, err := c.UseCase.React(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to react to post : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReactionSummaryResponse]{Data: response})
+}
+
+// Unreact godoc
+// @Tags Reactions
+// @Summary Remove a reaction from a post.
+// @Description API remove a reaction of the logged in user from a post.
+// @Security Bearer
+// @ID unreact-post
+// @Router /api/posts/{slug}/reactions/{type} [delete]
+// @Param slug path string true "Post Slug"
+// @Param type path string true "Reaction Type"
+// @Produce json
+// @Success 200
+func (c *ReactionController) Unreact(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := &model.ReactionRequest{
+		Slug:   ctx.Params("slug"),
+		UserId: user.ID,
+		Type:   ctx.Params("type"),
+	}
+
+	response, err := c.UseCase.Unreact(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to remove reaction : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReactionSummaryResponse]{Data: response})
+}
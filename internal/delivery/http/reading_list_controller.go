@@ -0,0 +1,297 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+)
+
+type ReadingListController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ReadingListUseCase
+}
+
+func NewReadingListController(logger *logrus.Logger, useCase *usecase.ReadingListUseCase) *ReadingListController {
+	return &ReadingListController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// ListOwn godoc
+// @Tags Reading Lists
+// @Summary Get own reading lists.
+// @Description API get every reading list of the logged in user, private ones included.
+// @Security Bearer
+// @ID get-own-reading-lists
+// @Router /api/users/me/lists [get]
+// @Produce json
+// @Success 200
+func (c *ReadingListController) ListOwn(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.ListOwn(ctx.UserContext(), user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to load reading lists : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]*model.ReadingListResponse]{Data: response})
+}
+
+// ListByUser godoc
+// @Tags Reading Lists
+// @Summary Get public reading lists of a user.
+// @Description API get the public reading lists of a user.
+// @ID get-user-reading-lists
+// @Router /api/users/{username}/lists [get]
+// @Param username path string true "Username"
+// @Produce json
+// @Success 200
+// @Success 301 "Username changed, redirects to the new URL"
+func (c *ReadingListController) ListByUser(ctx *fiber.Ctx) error {
+	username := ctx.Params("username")
+
+	response, err := c.UseCase.ListByUsername(ctx.UserContext(), username)
+	if err != nil {
+		if ok, err := redirectMovedUsernamePath(ctx, err, "/users/", "/lists"); ok {
+			return err
+		}
+		c.Log.Warnf("Failed to load reading lists : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]*model.ReadingListResponse]{Data: response})
+}
+
+// Get godoc
+// @Tags Reading Lists
+// @Summary Get a reading list.
+// @Description API get a reading list with its posts in order. Private lists are only visible to their owner.
+// @ID get-reading-list
+// @Router /api/lists/{listId} [get]
+// @Param listId path int true "Reading List ID"
+// @Produce json
+// @Success 200
+func (c *ReadingListController) Get(ctx *fiber.Ctx) error {
+	listId, err := ctx.ParamsInt("listId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := &model.GetReadingListRequest{
+		ID:       uint(listId),
+		ViewerId: middleware.GetViewerId(ctx),
+	}
+
+	response, err := c.UseCase.Get(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to load reading list : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReadingListResponse]{Data: response})
+}
+
+// Create godoc
+// @Tags Reading Lists
+// @Summary Create a reading list.
+// @Description API create a named reading list, private unless visibility is public.
+// @Security Bearer
+// @ID create-reading-list
+// @Router /api/users/me/lists [post]
+// @Param _ body model.CreateReadingListRequest true "Request create reading list"
+// @Accept json
+// @Produce json
+// @Success 201
+func (c *ReadingListController) Create(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := new(model.CreateReadingListRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create reading list : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.ReadingListResponse]{Data: response})
+}
+
+// Update godoc
+// @Tags Reading Lists
+// @Summary Update a reading list.
+// @Description API rename a reading list or change its description or visibility.
+// @Security Bearer
+// @ID update-reading-list
+// @Router /api/lists/{listId} [patch]
+// @Param listId path int true "Reading List ID"
+// @Param _ body model.UpdateReadingListRequest true "Request update reading list"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *ReadingListController) Update(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	listId, err := ctx.ParamsInt("listId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := new(model.UpdateReadingListRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.ID = uint(listId)
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update reading list : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReadingListResponse]{Data: response})
+}
+
+// Delete godoc
+// @Tags Reading Lists
+// @Summary Delete a reading list.
+// @Description API delete a reading list, the posts themselves are not affected.
+// @Security Bearer
+// @ID delete-reading-list
+// @Router /api/lists/{listId} [delete]
+// @Param listId path int true "Reading List ID"
+// @Produce json
+// @Success 200
+func (c *ReadingListController) Delete(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	listId, err := ctx.ParamsInt("listId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), uint(listId), user.ID); err != nil {
+		c.Log.Warnf("Failed to delete reading list : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[string]{Data: "Successfully delete reading list"})
+}
+
+// AddPost godoc
+// @Tags Reading Lists
+// @Summary Add a post to a reading list.
+// @Description API add a post to a reading list, at position (1 based) or at the end.
+// @Security Bearer
+// @ID add-reading-list-post
+// @Router /api/lists/{listId}/posts [post]
+// @Param listId path int true "Reading List ID"
+// @Param _ body model.ReadingListPostRequest true "Request add post"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *ReadingListController) AddPost(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	listId, err := ctx.ParamsInt("listId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := new(model.ReadingListPostRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.ListID = uint(listId)
+	request.UserId = user.ID
+
+	response, err := c.UseCase.AddPost(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to add post to reading list : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReadingListResponse]{Data: response})
+}
+
+// RemovePost godoc
+// @Tags Reading Lists
+// @Summary Remove a post from a reading list.
+// @Description API remove a post from a reading list.
+// @Security Bearer
+// @ID remove-reading-list-post
+// @Router /api/lists/{listId}/posts/{slug} [delete]
+// @Param listId path int true "Reading List ID"
+// @Param slug path string true "Post Slug"
+// @Produce json
+// @Success 200
+func (c *ReadingListController) RemovePost(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	listId, err := ctx.ParamsInt("listId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := &model.ReadingListPostRequest{
+		ListID: uint(listId),
+		UserId: user.ID,
+		Slug:   ctx.Params("slug"),
+	}
+
+	response, err := c.UseCase.RemovePost(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to remove post from reading list : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReadingListResponse]{Data: response})
+}
+
+// Reorder godoc
+// @Tags Reading Lists
+// @Summary Reorder a reading list.
+// @Description API set the order of a reading list, slugs must name every post of the list once.
+// @Security Bearer
+// @ID reorder-reading-list
+// @Router /api/lists/{listId}/posts [put]
+// @Param listId path int true "Reading List ID"
+// @Param _ body model.ReorderReadingListRequest true "Request reorder reading list"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *ReadingListController) Reorder(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	listId, err := ctx.ParamsInt("listId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := new(model.ReorderReadingListRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.ListID = uint(listId)
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Reorder(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to reorder reading list : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReadingListResponse]{Data: response})
+}
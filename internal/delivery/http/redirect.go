@@ -0,0 +1,28 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"go-blog/internal/usecase"
+	"net/url"
+)
+
+// redirectMovedUsername answers with a permanent redirect to prefix + the new username when err
+// is a *usecase.UsernameMovedError, the query string is kept. ok is false for any other error.
+func redirectMovedUsername(ctx *fiber.Ctx, err error, prefix string) (bool, error) {
+	return redirectMovedUsernamePath(ctx, err, prefix, "")
+}
+
+// redirectMovedUsernamePath is redirectMovedUsername for URLs that go on after the username.
+func redirectMovedUsernamePath(ctx *fiber.Ctx, err error, prefix string, suffix string) (bool, error) {
+	var moved *usecase.UsernameMovedError
+	if !errors.As(err, &moved) {
+		return false, nil
+	}
+
+	location := prefix + url.PathEscape(moved.Username) + suffix
+	if query := string(ctx.Request().URI().QueryString()); query != "" {
+		location += "?" + query
+	}
+	return true, ctx.Redirect(location, fiber.StatusMovedPermanently)
+}
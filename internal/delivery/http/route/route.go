@@ -8,45 +8,181 @@ import (
 )
 
 type RouteConfig struct {
-	App            fiber.Router
-	UserController *http.UserController
-	PostController *http.PostController
-	AuthMiddleware *middleware.Middleware
-	Config         *viper.Viper
+	App                    fiber.Router
+	UserController         *http.UserController
+	PostController         *http.PostController
+	PostAuthorController   *http.PostAuthorController
+	ExportController       *http.ExportController
+	CommentController      *http.CommentController
+	ReactionController     *http.ReactionController
+	BookmarkController     *http.BookmarkController
+	ReadingListController  *http.ReadingListController
+	SeriesController       *http.SeriesController
+	FollowController       *http.FollowController
+	NotificationController *http.NotificationController
+	EventController        *http.EventController
+	SyndicationController  *http.SyndicationController
+	SitemapController      *http.SitemapController
+	SiteController         *http.SiteController
+	MediaController        *http.MediaController
+	AuthMiddleware         *middleware.Middleware
+	Config                 *viper.Viper
 }
 
 func (c *RouteConfig) Setup() {
 	c.SetupViewsRoutes()
-	c.SetupGuestRoutes()
+	// Protected routes go first so static paths like /users/me win over /users/:username.
 	c.SetupProtectedRoutes()
+	c.SetupGuestRoutes()
 }
 func (c *RouteConfig) SetupViewsRoutes() {
-	c.App.Get("/home", func(ctx *fiber.Ctx) error {
-		return ctx.Render("home", fiber.Map{
-			"Name": "Adi Wahyudi",
-		})
-	})
+	c.App.Get("/", c.SiteController.Home)
+	c.App.Get("/page/:page", c.SiteController.Home)
+	c.App.Get("/article/:slug", c.SiteController.Post)
+	c.App.Get("/tag/:slug", c.SiteController.Tag)
+	c.App.Get("/tag/:slug/page/:page", c.SiteController.Tag)
+	c.App.Get("/author/:username", c.SiteController.Author)
+	c.App.Get("/author/:username/page/:page", c.SiteController.Author)
+	c.App.Get("/series/:slug", c.SiteController.Series)
+	c.App.Get("/archive", c.SiteController.Archive)
+	c.App.Get("/search", c.SiteController.Search)
 }
+
 func (c *RouteConfig) SetupGuestRoutes() {
 	// Auth
 	auth := c.App.Group("/auth")
 	auth.Post("/login", c.UserController.Login)
 	auth.Post("/register", c.UserController.RegisterUser)
+	auth.Post("/unlock", c.AuthMiddleware.BasicAuth, c.UserController.UnlockLogin)
+	auth.Get("/email/confirm", c.UserController.ConfirmEmail)
+	auth.Post("/restore", c.UserController.RestoreCurrent)
 
 	// Post
-	c.App.Get("/posts", c.PostController.List)
-	c.App.Get("/posts/:username", c.PostController.ListByUser)
-	c.App.Get("/post/:slug", c.PostController.FindBySlug)
+	// OptionalJWT lets logged in readers see their own reactions
+	c.App.Get("/posts", c.AuthMiddleware.OptionalJWT, c.PostController.List)
+	c.App.Get("/posts/:username", c.AuthMiddleware.OptionalJWT, c.PostController.ListByUser)
+	c.App.Get("/post/:slug", c.AuthMiddleware.OptionalJWT, c.PostController.FindBySlug)
+	c.App.Get("/post/:slug/comments", c.CommentController.List)
+
+	// Users
+	c.App.Get("/users/:username", c.UserController.Profile)
+	c.App.Get("/users/:username/lists", c.ReadingListController.ListByUser)
+
+	// Reading lists, private ones are only shown to their owner
+	c.App.Get("/lists/:listId", c.AuthMiddleware.OptionalJWT, c.ReadingListController.Get)
+
+	// Series, /series/:slug itself is the rendered page
+	c.App.Get("/users/:username/series", c.SeriesController.ListByUser)
+	c.App.Get("/series/:slug/posts", c.SeriesController.Get)
+
+	// Feeds of the site, an author, a tag and a series
+	c.App.Get("/feed.xml", c.SyndicationController.RSS)
+	c.App.Get("/atom.xml", c.SyndicationController.Atom)
+	c.App.Get("/feed.json", c.SyndicationController.JSONFeed)
+	c.App.Get("/posts/:username/feed.xml", c.SyndicationController.RSS)
+	c.App.Get("/posts/:username/atom.xml", c.SyndicationController.Atom)
+	c.App.Get("/posts/:username/feed.json", c.SyndicationController.JSONFeed)
+	c.App.Get("/tags/:slug/feed.xml", c.SyndicationController.RSS)
+	c.App.Get("/tags/:slug/atom.xml", c.SyndicationController.Atom)
+	c.App.Get("/tags/:slug/feed.json", c.SyndicationController.JSONFeed)
+	c.App.Get("/series/:series/feed.xml", c.SyndicationController.RSS)
+	c.App.Get("/series/:series/atom.xml", c.SyndicationController.Atom)
+	c.App.Get("/series/:series/feed.json", c.SyndicationController.JSONFeed)
+
+	// Sitemap and robots.txt
+	c.App.Get("/sitemap.xml", c.SitemapController.Index)
+	c.App.Get("/sitemaps/:section/:page.xml", c.SitemapController.Page)
+	c.App.Get("/robots.txt", c.SitemapController.Robots)
+
+	// Exports, the signed link is the authorization
+	c.App.Get("/exports/:exportId/download", c.ExportController.Download)
 }
 
 func (c *RouteConfig) SetupProtectedRoutes() {
+	// The middleware is attached per route, a group level one would also catch the guest routes sharing the prefix.
+	auth := c.AuthMiddleware.ValidateJWT
 
 	// Users
 	users := c.App.Group("/users")
-	users.Patch("", c.UserController.Update)
-	users.Patch("/:userId", c.UserController.Delete)
+	users.Patch("", auth, c.UserController.Update)
+	users.Get("/me", auth, c.UserController.Current)
+	users.Patch("/me", auth, c.UserController.Update)
+	users.Delete("/me", auth, c.UserController.DeleteCurrent)
+	users.Get("/me/comments", auth, c.CommentController.ListOwnModeration)
+	users.Post("/me/exports", auth, c.ExportController.Create)
+	users.Get("/me/exports/:exportId", auth, c.ExportController.Get)
+	users.Get("/me/lists", auth, c.ReadingListController.ListOwn)
+	users.Post("/me/lists", auth, c.ReadingListController.Create)
+	users.Get("/me/invitations", auth, c.PostAuthorController.ListInvitations)
+	users.Get("/me/series", auth, c.SeriesController.ListOwn)
+	users.Post("/me/series", auth, c.SeriesController.Create)
+	users.Get("/me/following", auth, c.FollowController.ListFollowing)
+	users.Get("/me/media", auth, c.MediaController.List)
+	users.Post("/:username/follow", auth, c.FollowController.FollowUser)
+	users.Delete("/:username/follow", auth, c.FollowController.UnfollowUser)
+
+	// Admin
+	users.Delete("/:userId", c.AuthMiddleware.BasicAuth, c.UserController.Delete)
+	users.Post("/:userId/restore", c.AuthMiddleware.BasicAuth, c.UserController.Restore)
 
 	// Post
 	posts := c.App.Group("/posts")
-	posts.Post("", c.PostController.CreatePost)
+	posts.Post("", auth, c.PostController.CreatePost)
+	posts.Patch("/:slug", auth, c.PostController.Update)
+	posts.Get("/:slug/authors", auth, c.PostAuthorController.List)
+	posts.Post("/:slug/authors", auth, c.PostAuthorController.Invite)
+	posts.Post("/:slug/authors/accept", auth, c.PostAuthorController.Accept)
+	posts.Delete("/:slug/authors/:username", auth, c.PostAuthorController.Remove)
+	posts.Post("/:slug/reactions", auth, c.ReactionController.React)
+	posts.Delete("/:slug/reactions/:type", auth, c.ReactionController.Unreact)
+	posts.Post("/:slug/bookmark", auth, c.BookmarkController.Add)
+	posts.Delete("/:slug/bookmark", auth, c.BookmarkController.Remove)
+
+	// Feed and tags
+	c.App.Get("/feed", auth, c.PostController.Feed)
+	c.App.Post("/tags/:slug/follow", auth, c.FollowController.FollowTag)
+	c.App.Delete("/tags/:slug/follow", auth, c.FollowController.UnfollowTag)
+
+	// Events, EventSource cannot set headers so the token may come as access_token query
+	c.App.Get("/events", c.AuthMiddleware.ValidateJWTOrQuery, c.EventController.Stream)
+
+	// Notifications
+	notifications := c.App.Group("/notifications")
+	notifications.Get("", auth, c.NotificationController.List)
+	notifications.Post("/read", auth, c.NotificationController.MarkAllRead)
+	notifications.Post("/:notificationId/read", auth, c.NotificationController.MarkRead)
+	notifications.Get("/preferences", auth, c.NotificationController.GetPreferences)
+	notifications.Patch("/preferences", auth, c.NotificationController.UpdatePreferences)
+
+	// Reading lists
+	lists := c.App.Group("/lists")
+	lists.Patch("/:listId", auth, c.ReadingListController.Update)
+	lists.Delete("/:listId", auth, c.ReadingListController.Delete)
+	lists.Post("/:listId/posts", auth, c.ReadingListController.AddPost)
+	lists.Put("/:listId/posts", auth, c.ReadingListController.Reorder)
+	lists.Delete("/:listId/posts/:slug", auth, c.ReadingListController.RemovePost)
+
+	// Series
+	series := c.App.Group("/series")
+	series.Patch("/:seriesId", auth, c.SeriesController.Update)
+	series.Delete("/:seriesId", auth, c.SeriesController.Delete)
+	series.Post("/:seriesId/posts", auth, c.SeriesController.AddPost)
+	series.Put("/:seriesId/posts", auth, c.SeriesController.Reorder)
+	series.Delete("/:seriesId/posts/:slug", auth, c.SeriesController.RemovePost)
+
+	// Media
+	c.App.Post("/media", auth, c.MediaController.Upload)
+	c.App.Delete("/media/:mediaId", auth, c.MediaController.Delete)
+
+	// Comments
+	c.App.Post("/post/:slug/comments", auth, c.CommentController.Create)
+	comments := c.App.Group("/comments")
+	comments.Patch("/:commentId", auth, c.CommentController.Update)
+	comments.Delete("/:commentId", auth, c.CommentController.Delete)
+	comments.Patch("/:commentId/moderation", auth, c.CommentController.ModerateOwn)
+
+	// Admin
+	admin := c.App.Group("/admin", c.AuthMiddleware.BasicAuth)
+	admin.Get("/comments", c.CommentController.ListModeration)
+	admin.Patch("/comments/:commentId", c.CommentController.Moderate)
 }
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/delivery/http/middleware"
+	"go-blog/internal/model"
+	"go-blog/internal/usecase"
+)
+
+type SeriesController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.SeriesUseCase
+}
+
+func NewSeriesController(logger *logrus.Logger, useCase *usecase.SeriesUseCase) *SeriesController {
+	return &SeriesController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// ListOwn godoc
+// @Tags Series
+// @Summary Get own series.
+// @Description API get every series of the logged in user, the post count includes parts that are not published yet.
+// @Security Bearer
+// @ID get-own-series
+// @Router /api/users/me/series [get]
+// @Produce json
+// @Success 200
+func (c *SeriesController) ListOwn(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.ListOwn(ctx.UserContext(), user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to load series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]*model.SeriesResponse]{Data: response})
+}
+
+// ListByUser godoc
+// @Tags Series
+// @Summary Get the series of a user.
+// @Description API get the series of a user with the number of their published parts.
+// @ID get-user-series
+// @Router /api/users/{username}/series [get]
+// @Param username path string true "Username"
+// @Produce json
+// @Success 200
+// @Success 301 "Username changed, redirects to the new URL"
+func (c *SeriesController) ListByUser(ctx *fiber.Ctx) error {
+	username := ctx.Params("username")
+
+	response, err := c.UseCase.ListByUsername(ctx.UserContext(), username)
+	if err != nil {
+		if ok, err := redirectMovedUsernamePath(ctx, err, "/users/", "/series"); ok {
+			return err
+		}
+		c.Log.Warnf("Failed to load series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[[]*model.SeriesResponse]{Data: response})
+}
+
+// Get godoc
+// @Tags Series
+// @Summary Get a series.
+// @Description API get a series with its published posts in order.
+// @ID get-series
+// @Router /api/series/{slug}/posts [get]
+// @Param slug path string true "Series Slug"
+// @Produce json
+// @Success 200
+func (c *SeriesController) Get(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.Get(ctx.UserContext(), ctx.Params("slug"))
+	if err != nil {
+		c.Log.Warnf("Failed to load series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SeriesResponse]{Data: response})
+}
+
+// Create godoc
+// @Tags Series
+// @Summary Create a series.
+// @Description API create a series, its slug comes from the title and stays when the title changes.
+// @Security Bearer
+// @ID create-series
+// @Router /api/users/me/series [post]
+// @Param _ body model.CreateSeriesRequest true "Request create series"
+// @Accept json
+// @Produce json
+// @Success 201
+func (c *SeriesController) Create(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	request := new(model.CreateSeriesRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to create series : %+v", err)
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.SeriesResponse]{Data: response})
+}
+
+// Update godoc
+// @Tags Series
+// @Summary Update a series.
+// @Description API rename a series or change its description, the slug is kept.
+// @Security Bearer
+// @ID update-series
+// @Router /api/series/{seriesId} [patch]
+// @Param seriesId path int true "Series ID"
+// @Param _ body model.UpdateSeriesRequest true "Request update series"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *SeriesController) Update(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	seriesId, err := ctx.ParamsInt("seriesId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := new(model.UpdateSeriesRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.ID = uint(seriesId)
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to update series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SeriesResponse]{Data: response})
+}
+
+// Delete godoc
+// @Tags Series
+// @Summary Delete a series.
+// @Description API delete a series, its posts are not affected and become standalone again.
+// @Security Bearer
+// @ID delete-series
+// @Router /api/series/{seriesId} [delete]
+// @Param seriesId path int true "Series ID"
+// @Produce json
+// @Success 200
+func (c *SeriesController) Delete(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	seriesId, err := ctx.ParamsInt("seriesId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	if err := c.UseCase.Delete(ctx.UserContext(), uint(seriesId), user.ID); err != nil {
+		c.Log.Warnf("Failed to delete series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[string]{Data: "Successfully delete series"})
+}
+
+// AddPost godoc
+// @Tags Series
+// @Summary Add a post to a series.
+// @Description API add one of your posts to a series, at position (1 based) or at the end. A post can be part of one series only.
+// @Security Bearer
+// @ID add-series-post
+// @Router /api/series/{seriesId}/posts [post]
+// @Param seriesId path int true "Series ID"
+// @Param _ body model.SeriesPostRequest true "Request add post"
+// @Accept json
+// @Produce json
+// @Success 200
+// @Success 409 "Post is already part of a series"
+func (c *SeriesController) AddPost(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	seriesId, err := ctx.ParamsInt("seriesId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := new(model.SeriesPostRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.SeriesID = uint(seriesId)
+	request.UserId = user.ID
+
+	response, err := c.UseCase.AddPost(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to add post to series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SeriesResponse]{Data: response})
+}
+
+// RemovePost godoc
+// @Tags Series
+// @Summary Remove a post from a series.
+// @Description API remove a post from a series.
+// @Security Bearer
+// @ID remove-series-post
+// @Router /api/series/{seriesId}/posts/{slug} [delete]
+// @Param seriesId path int true "Series ID"
+// @Param slug path string true "Post Slug"
+// @Produce json
+// @Success 200
+func (c *SeriesController) RemovePost(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	seriesId, err := ctx.ParamsInt("seriesId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := &model.SeriesPostRequest{
+		SeriesID: uint(seriesId),
+		UserId:   user.ID,
+		Slug:     ctx.Params("slug"),
+	}
+
+	response, err := c.UseCase.RemovePost(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to remove post from series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SeriesResponse]{Data: response})
+}
+
+// Reorder godoc
+// @Tags Series
+// @Summary Reorder a series.
+// @Description API set the order of a series, slugs must name every post of the series once.
+// @Security Bearer
+// @ID reorder-series
+// @Router /api/series/{seriesId}/posts [put]
+// @Param seriesId path int true "Series ID"
+// @Param _ body model.ReorderSeriesRequest true "Request reorder series"
+// @Accept json
+// @Produce json
+// @Success 200
+func (c *SeriesController) Reorder(ctx *fiber.Ctx) error {
+	user := middleware.GetUser(ctx)
+
+	seriesId, err := ctx.ParamsInt("seriesId")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	request := new(model.ReorderSeriesRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.SeriesID = uint(seriesId)
+	request.UserId = user.ID
+
+	response, err := c.UseCase.Reorder(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.Warnf("Failed to reorder series : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SeriesResponse]{Data: response})
+}
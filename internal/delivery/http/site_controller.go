@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/usecase"
+)
+
+// SiteController serves the server-rendered blog. Errors are rendered as HTML pages instead of
+// going through the JSON error handler.
+type SiteController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.SiteUseCase
+}
+
+func NewSiteController(logger *logrus.Logger, useCase *usecase.SiteUseCase) *SiteController {
+	return &SiteController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *SiteController) Home(ctx *fiber.Ctx) error {
+	page, err := c.UseCase.Home(ctx.UserContext(), pageParam(ctx))
+	return c.render(ctx, "home", page, err)
+}
+
+func (c *SiteController) Post(ctx *fiber.Ctx) error {
+	page, err := c.UseCase.Post(ctx.UserContext(), ctx.Params("slug"))
+	return c.render(ctx, "post", page, err)
+}
+
+func (c *SiteController) Tag(ctx *fiber.Ctx) error {
+	page, err := c.UseCase.Tag(ctx.UserContext(), ctx.Params("slug"), pageParam(ctx))
+	return c.render(ctx, "list", page, err)
+}
+
+func (c *SiteController) Author(ctx *fiber.Ctx) error {
+	page, err := c.UseCase.Author(ctx.UserContext(), ctx.Params("username"), pageParam(ctx))
+	if ok, err := redirectMovedUsername(ctx, err, "/author/"); ok {
+		return err
+	}
+	return c.render(ctx, "list", page, err)
+}
+
+func (c *SiteController) Series(ctx *fiber.Ctx) error {
+	page, err := c.UseCase.Series(ctx.UserContext(), ctx.Params("slug"))
+	return c.render(ctx, "series", page, err)
+}
+
+func (c *SiteController) Search(ctx *fiber.Ctx) error {
+	page, err := c.UseCase.Search(ctx.UserContext(), ctx.Query("q"), ctx.QueryInt("page", 1))
+	return c.render(ctx, "search", page, err)
+}
+
+func (c *SiteController) Archive(ctx *fiber.Ctx) error {
+	page, err := c.UseCase.Archive(ctx.UserContext())
+	return c.render(ctx, "archive", page, err)
+}
+
+func (c *SiteController) render(ctx *fiber.Ctx, name string, page any, err error) error {
+	if err == nil {
+		return ctx.Render(name, page, usecase.SiteLayout)
+	}
+
+	code := fiber.StatusInternalServerError
+	message := "Something went wrong"
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		code = fiberErr.Code
+		message = fiberErr.Message
+	}
+	if code >= fiber.StatusInternalServerError {
+		c.Log.Warnf("Failed to render %s page : %+v", name, err)
+	}
+	return ctx.Status(code).Render("error", c.UseCase.ErrorPage(code, message), usecase.SiteLayout)
+}
+
+// pageParam is the :page of a list, 0 when it is not a number so the list answers not found.
+func pageParam(ctx *fiber.Ctx) int {
+	if ctx.Params("page") == "" {
+		return 1
+	}
+	page, err := ctx.ParamsInt("page")
+	if err != nil {
+		return 0
+	}
+	return page
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"encoding/xml"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/usecase"
+	"strconv"
+)
+
+type SitemapController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.SitemapUseCase
+}
+
+func NewSitemapController(logger *logrus.Logger, useCase *usecase.SitemapUseCase) *SitemapController {
+	return &SitemapController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Index godoc
+// @Tags SEO
+// @Summary Get the sitemap index.
+// @Description Lists the sitemap pages of posts, tags and authors, each page holds at most 50000 URLs.
+// @ID get-sitemap-index
+// @Router /sitemap.xml [get]
+// @Produce xml
+// @Success 200
+// @Success 304
+func (c *SitemapController) Index(ctx *fiber.Ctx) error {
+	index, err := c.UseCase.Index(ctx.UserContext())
+	if err != nil {
+		c.Log.Warnf("Failed to load sitemap index : %+v", err)
+		return err
+	}
+	return c.send(ctx, index)
+}
+
+// Page godoc
+// @Tags SEO
+// @Summary Get a sitemap page.
+// @Description A page of the posts, tags or authors sitemap, as linked from the sitemap index.
+// @ID get-sitemap-page
+// @Router /sitemaps/{section}/{page}.xml [get]
+// @Param section path string true "posts, tags or authors"
+// @Param page path int true "Page Number"
+// @Produce xml
+// @Success 200
+// @Success 304
+func (c *SitemapController) Page(ctx *fiber.Ctx) error {
+	page, err := strconv.Atoi(ctx.Params("page"))
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	urlSet, err := c.UseCase.Page(ctx.UserContext(), ctx.Params("section"), page)
+	if err != nil {
+		c.Log.Warnf("Failed to load sitemap page : %+v", err)
+		return err
+	}
+	return c.send(ctx, urlSet)
+}
+
+// Robots godoc
+// @Tags SEO
+// @Summary Get robots.txt.
+// @ID get-robots
+// @Router /robots.txt [get]
+// @Produce plain
+// @Success 200
+func (c *SitemapController) Robots(ctx *fiber.Ctx) error {
+	return sendCacheable(ctx, fiber.MIMETextPlainCharsetUTF8, []byte(c.UseCase.Robots()), nil)
+}
+
+func (c *SitemapController) send(ctx *fiber.Ctx, document any) error {
+	body, err := xml.Marshal(document)
+	if err != nil {
+		c.Log.Warnf("Failed to marshal sitemap : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return sendCacheable(ctx, fiber.MIMEApplicationXMLCharsetUTF8, append([]byte(xml.Header), body...), nil)
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/usecase"
+)
+
+type SyndicationController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.SyndicationUseCase
+}
+
+func NewSyndicationController(logger *logrus.Logger, useCase *usecase.SyndicationUseCase) *SyndicationController {
+	return &SyndicationController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// RSS godoc
+// @Tags Feeds
+// @Summary Get the RSS 2.0 feed.
+// @Description The latest published posts of the site, an author, a tag or a series. Supports If-None-Match and If-Modified-Since.
+// @ID get-rss-feed
+// @Router /feed.xml [get]
+// @Router /posts/{username}/feed.xml [get]
+// @Router /tags/{slug}/feed.xml [get]
+// @Router /series/{series}/feed.xml [get]
+// @Param username path string false "Username"
+// @Param slug path string false "Tag Slug"
+// @Param series path string false "Series Slug"
+// @Produce xml
+// @Success 200
+// @Success 304
+func (c *SyndicationController) RSS(ctx *fiber.Ctx) error {
+	feed, err := c.build(ctx, converter.RSSPath)
+	if err != nil || feed == nil {
+		return err
+	}
+
+	body, err := xml.MarshalIndent(converter.SyndicationToRSS(feed), "", "  ")
+	if err != nil {
+		c.Log.Warnf("Failed to marshal rss feed : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return sendCacheable(ctx, "application/rss+xml; charset=utf-8", append([]byte(xml.Header), body...), feed.Updated)
+}
+
+// Atom godoc
+// @Tags Feeds
+// @Summary Get the Atom feed.
+// @Description The latest published posts of the site, an author, a tag or a series. Supports If-None-Match and If-Modified-Since.
+// @ID get-atom-feed
+// @Router /atom.xml [get]
+// @Router /posts/{username}/atom.xml [get]
+// @Router /tags/{slug}/atom.xml [get]
+// @Router /series/{series}/atom.xml [get]
+// @Param username path string false "Username"
+// @Param slug path string false "Tag Slug"
+// @Param series path string false "Series Slug"
+// @Produce xml
+// @Success 200
+// @Success 304
+func (c *SyndicationController) Atom(ctx *fiber.Ctx) error {
+	feed, err := c.build(ctx, converter.AtomPath)
+	if err != nil || feed == nil {
+		return err
+	}
+
+	body, err := xml.MarshalIndent(converter.SyndicationToAtom(feed), "", "  ")
+	if err != nil {
+		c.Log.Warnf("Failed to marshal atom feed : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return sendCacheable(ctx, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), body...), feed.Updated)
+}
+
+// JSONFeed godoc
+// @Tags Feeds
+// @Summary Get the JSON Feed.
+// @Description The latest published posts of the site, an author, a tag or a series as JSON Feed 1.1. Supports If-None-Match and If-Modified-Since.
+// @ID get-json-feed
+// @Router /feed.json [get]
+// @Router /posts/{username}/feed.json [get]
+// @Router /tags/{slug}/feed.json [get]
+// @Router /series/{series}/feed.json [get]
+// @Param username path string false "Username"
+// @Param slug path string false "Tag Slug"
+// @Param series path string false "Series Slug"
+// @Produce json
+// @Success 200
+// @Success 304
+func (c *SyndicationController) JSONFeed(ctx *fiber.Ctx) error {
+	feed, err := c.build(ctx, converter.JSONFeedPath)
+	if err != nil || feed == nil {
+		return err
+	}
+
+	body, err := json.Marshal(converter.SyndicationToJSONFeed(feed))
+	if err != nil {
+		c.Log.Warnf("Failed to marshal json feed : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return sendCacheable(ctx, "application/feed+json; charset=utf-8", body, feed.Updated)
+}
+
+// build loads the feed the route points at, a nil feed with a nil error means a redirect was sent.
+func (c *SyndicationController) build(ctx *fiber.Ctx, path string) (*model.SyndicationFeed, error) {
+	request := &model.SyndicationRequest{
+		Username: ctx.Params("username"),
+		Tag:      ctx.Params("slug"),
+		Series:   ctx.Params("series"),
+	}
+
+	feed, err := c.UseCase.Build(ctx.UserContext(), request)
+	if err != nil {
+		if ok, err := redirectMovedUsernamePath(ctx, err, "/posts/", path); ok {
+			return nil, err
+		}
+		c.Log.Warnf("Failed to build feed : %+v", err)
+		return nil, err
+	}
+	return feed, nil
+}
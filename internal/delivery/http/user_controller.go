@@ -1,22 +1,26 @@
 package http
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"go-blog/internal/delivery/http/middleware"
 	"go-blog/internal/model"
 	"go-blog/internal/usecase"
+	"strconv"
 )
 
 type UserController struct {
-	Log     *logrus.Logger
-	UseCase *usecase.UserUseCase
+	Log                  *logrus.Logger
+	UseCase              *usecase.UserUseCase
+	LoginThrottleUseCase *usecase.LoginThrottleUseCase
 }
 
-func NewUserController(logger *logrus.Logger, useCase *usecase.UserUseCase) *UserController {
+func NewUserController(logger *logrus.Logger, useCase *usecase.UserUseCase, loginThrottleUseCase *usecase.LoginThrottleUseCase) *UserController {
 	return &UserController{
-		Log:     logger,
-		UseCase: useCase,
+		Log:                  logger,
+		UseCase:              useCase,
+		LoginThrottleUseCase: loginThrottleUseCase,
 	}
 }
 
@@ -58,33 +62,68 @@ func (c *UserController) RegisterUser(ctx *fiber.Ctx) error {
 // @Param register body model.LoginUserRequest true "Request"
 // @Produce json
 // @Success 201
+// @Failure 429 "Too many failed attempts, see Retry-After header"
 func (c *UserController) Login(ctx *fiber.Ctx) error {
 	request := new(model.LoginUserRequest)
 	if err := ctx.BodyParser(request); err != nil {
 		c.Log.Warnf("Failed to parse request body : %+v", err)
 		return fiber.ErrBadRequest
 	}
+	request.IP = ctx.IP()
 
 	response, err := c.UseCase.Login(ctx.UserContext(), request)
 	if err != nil {
 		c.Log.Warnf("Failed to register user : %+v", err)
+
+		var locked *usecase.LoginLockedError
+		if errors.As(err, &locked) {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(locked.RetryAfterSeconds()))
+			return fiber.NewError(fiber.StatusTooManyRequests, locked.Error())
+		}
 		return err
 	}
 
 	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
 }
 
+// UnlockLogin godoc
+// @Tags Auth
+// @Summary Unlock login
+// @Description API for clearing a login lockout by email and/or IP address, restricted for admin only.
+// @ID unlock-login
+// @Security BasicAuth
+// @Router /api/auth/unlock [post]
+// @Accept json
+// @Param unlock body model.UnlockLoginRequest true "Request"
+// @Produce json
+// @Success 200
+func (c *UserController) UnlockLogin(ctx *fiber.Ctx) error {
+	request := new(model.UnlockLoginRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	if err := c.LoginThrottleUseCase.Unlock(ctx.UserContext(), request); err != nil {
+		c.Log.Warnf("Failed to unlock login : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[string]{Data: "Successfully unlock login"})
+}
+
 // Update godoc
 // @Tags Users
 // @Summary Update User
 // @Description API for update user that currently logged in.
 // @ID update-current-user
 // @Security Bearer
-// @Router /api/users [patch]
+// @Router /api/users/me [patch]
 // @Accept json
 // @Param register body model.UpdateUserRequest true "Request"
 // @Produce json
-// @Success 201
+// @Success 200
+// @Failure 409 "Username or email already used"
 func (c *UserController) Update(ctx *fiber.Ctx) error {
 	auth := middleware.GetUser(ctx)
 	request := new(model.UpdateUserRequest)
@@ -95,34 +134,195 @@ func (c *UserController) Update(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	if err := c.UseCase.Update(ctx.UserContext(), request); err != nil {
+	response, err := c.UseCase.Update(ctx.UserContext(), request)
+	if err != nil {
 		c.Log.WithError(err).Error("error updating user")
 		return err
 	}
 
-	return ctx.JSON(model.WebResponse[string]{Data: "Successfully update user"})
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+
+}
+
+// Current godoc
+// @Tags Users
+// @Summary Get current user
+// @Description API for get the user that currently logged in.
+// @ID get-current-user
+// @Security Bearer
+// @Router /api/users/me [get]
+// @Produce json
+// @Success 200
+func (c *UserController) Current(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+
+	response, err := c.UseCase.Current(ctx.UserContext(), auth.ID)
+	if err != nil {
+		c.Log.WithError(err).Error("error getting current user")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// ConfirmEmail godoc
+// @Tags Auth
+// @Summary Confirm email change
+// @Description API for confirm a pending email change with the token sent to the new address.
+// @ID confirm-email
+// @Router /api/auth/email/confirm [get]
+// @Param token query string true "Confirmation token"
+// @Produce json
+// @Success 200
+// @Failure 409 "Email already used"
+func (c *UserController) ConfirmEmail(ctx *fiber.Ctx) error {
+	request := &model.ConfirmEmailRequest{
+		Token: ctx.Query("token"),
+	}
+
+	response, err := c.UseCase.ConfirmEmail(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.WithError(err).Error("error confirming email")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// Profile godoc
+// @Tags Users
+// @Summary Get public profile of a user.
+// @Description API for get public author profile with post count and latest posts.
+// @ID get-user-profile
+// @Router /api/users/{username} [get]
+// @Accept json
+// @Param username path string true "Username"
+// @Produce json
+// @Success 200
+// @Success 301 "Username changed, redirects to the new profile URL"
+func (c *UserController) Profile(ctx *fiber.Ctx) error {
+	username := ctx.Params("username")
+
+	response, err := c.UseCase.GetProfile(ctx.UserContext(), username)
+	if err != nil {
+		if ok, err := redirectMovedUsername(ctx, err, "/users/"); ok {
+			return err
+		}
+		c.Log.Warnf("Failed to load profile : %+v", err)
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ProfileResponse]{Data: response})
+}
+
+// DeleteCurrent godoc
+// @Tags Users
+// @Summary Delete current user
+// @Description API for delete the user that currently logged in. Posts are anonymized under a "former author" placeholder or deleted. The account can be restored until purge_after.
+// @ID delete-current-user
+// @Security Bearer
+// @Router /api/users/me [delete]
+// @Accept json
+// @Param delete body model.DeleteUserRequest true "Request"
+// @Produce json
+// @Success 200
+func (c *UserController) DeleteCurrent(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.DeleteUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithError(err).Error("error parsing request body")
+		return fiber.ErrBadRequest
+	}
+	request.ID = auth.ID
+
+	response, err := c.UseCase.DeleteCurrent(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.WithError(err).Error("error deleting user")
+		return err
+	}
 
+	return ctx.JSON(model.WebResponse[*model.DeleteUserResponse]{Data: response})
 }
 
 // Delete godoc
 // @Tags Users
 // @Summary Delete user by ID.
-// @Description API for delete user by ID, restricted for admin only.
+// @Description API for delete user by ID, restricted for admin only. Posts are transferred to another author, anonymized under a "former author" placeholder or deleted.
 // @ID delete-user
-// @Security Bearer
+// @Security BasicAuth
 // @Router /api/users/{userId} [delete]
 // @Accept json
 // @Param userId path string true "User ID"
+// @Param delete body model.DeleteUserRequest true "Request"
 // @Produce json
-// @Success 201
+// @Success 200
 func (c *UserController) Delete(ctx *fiber.Ctx) error {
-	userId := ctx.Params("userId")
+	request := new(model.DeleteUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithError(err).Error("error parsing request body")
+		return fiber.ErrBadRequest
+	}
+	request.ID = ctx.Params("userId")
 
-	if err := c.UseCase.Delete(ctx.UserContext(), userId); err != nil {
-		c.Log.WithError(err).Error("error updating user")
+	response, err := c.UseCase.Delete(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.WithError(err).Error("error deleting user")
 		return err
 	}
 
-	return ctx.JSON(model.WebResponse[string]{Data: "Successfully delete user"})
+	return ctx.JSON(model.WebResponse[*model.DeleteUserResponse]{Data: response})
+
+}
 
+// Restore godoc
+// @Tags Users
+// @Summary Restore deleted user by ID.
+// @Description API for restore a deleted user during its restore window, restricted for admin only.
+// @ID restore-user
+// @Security BasicAuth
+// @Router /api/users/{userId}/restore [post]
+// @Param userId path string true "User ID"
+// @Produce json
+// @Success 200
+func (c *UserController) Restore(ctx *fiber.Ctx) error {
+	response, err := c.UseCase.Restore(ctx.UserContext(), ctx.Params("userId"))
+	if err != nil {
+		c.Log.WithError(err).Error("error restoring user")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
+}
+
+// RestoreCurrent godoc
+// @Tags Auth
+// @Summary Restore own deleted account
+// @Description API for restore your own deleted account with your credentials during the restore window.
+// @ID restore-current-user
+// @Router /api/auth/restore [post]
+// @Accept json
+// @Param restore body model.LoginUserRequest true "Request"
+// @Produce json
+// @Success 200
+func (c *UserController) RestoreCurrent(ctx *fiber.Ctx) error {
+	request := new(model.LoginUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+	request.IP = ctx.IP()
+
+	response, err := c.UseCase.RestoreCurrent(ctx.UserContext(), request)
+	if err != nil {
+		c.Log.WithError(err).Error("error restoring user")
+
+		var locked *usecase.LoginLockedError
+		if errors.As(err, &locked) {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(locked.RetryAfterSeconds()))
+			return fiber.NewError(fiber.StatusTooManyRequests, locked.Error())
+		}
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.UserResponse]{Data: response})
 }
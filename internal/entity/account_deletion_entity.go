@@ -0,0 +1,20 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	PostsStrategyTransfer  = "transfer"
+	PostsStrategyAnonymize = "anonymize"
+	PostsStrategyDelete    = "delete"
+)
+
+// AccountDeletion keeps track of a soft deleted user until it is purged or restored.
+type AccountDeletion struct {
+	UserID        string    `gorm:"primaryKey;type:varchar(36);not null"`
+	PostsStrategy string    `gorm:"type:varchar(10);not null"`
+	TransferredTo string    `gorm:"type:varchar(36)"`
+	DeletedAt     time.Time `gorm:"not null"`
+	PurgeAfter    time.Time `gorm:"not null;index"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"time"
+)
+
+type Bookmark struct {
+	ID        uint       `gorm:"primaryKey;not null"`
+	UserID    string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_bookmark_user_post"`
+	PostID    uint       `gorm:"not null;uniqueIndex:idx_bookmark_user_post;index"`
+	Post      Post       `gorm:"foreignKey:PostID;references:ID"`
+	CreatedAt *time.Time `gorm:"autoCreateTime"`
+}
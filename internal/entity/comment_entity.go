@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	CommentStatusApproved = "approved"
+	CommentStatusPending  = "pending"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+type Comment struct {
+	ID          uint           `gorm:"primaryKey;not null"`
+	PostID      uint           `gorm:"not null;index"`
+	UserID      string         `gorm:"type:varchar(36);not null;index"`
+	ParentID    *uint          `gorm:"index"`
+	RootID      *uint          `gorm:"index"`
+	Content     string         `gorm:"type:text;not null"`
+	Status      string         `gorm:"type:varchar(10);not null;default:approved;index"`
+	SpamScore   int            `gorm:"not null;default:0"`
+	SpamNotes   string         `gorm:"type:varchar(255)"`
+	ModeratedBy string         `gorm:"type:varchar(36)"`
+	ModeratedAt *time.Time     `gorm:"TIMESTAMP NULL"`
+	Post        Post           `gorm:"foreignKey:PostID;references:ID"`
+	User        User           `gorm:"foreignKey:UserID;references:ID"`
+	EditedAt    *time.Time     `gorm:"TIMESTAMP NULL"`
+	CreatedAt   *time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt   *time.Time     `gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	DataExportStatusPending = "pending"
+	DataExportStatusReady   = "ready"
+	DataExportStatusFailed  = "failed"
+)
+
+type DataExport struct {
+	ID          string     `gorm:"primaryKey;not null;type:varchar(36)"`
+	UserID      string     `gorm:"type:varchar(36);not null;index"`
+	Status      string     `gorm:"type:varchar(10);not null"`
+	FilePath    string     `gorm:"type:varchar(255)"`
+	Size        int64      `gorm:"not null;default:0"`
+	ExpiresAt   time.Time  `gorm:"not null;index"`
+	CompletedAt *time.Time `gorm:"TIMESTAMP NULL"`
+	CreatedAt   *time.Time `gorm:"autoCreateTime"`
+}
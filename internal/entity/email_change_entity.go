@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"time"
+)
+
+type EmailChange struct {
+	ID        uint       `gorm:"primaryKey;not null"`
+	UserID    string     `gorm:"type:varchar(36);not null;index"`
+	NewEmail  string     `gorm:"type:varchar(255);not null"`
+	TokenHash string     `gorm:"type:varchar(64);not null;unique"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	CreatedAt *time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+)
+
+type UserFollow struct {
+	ID         uint       `gorm:"primaryKey;not null"`
+	FollowerID string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_user_follow"`
+	FolloweeID string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_user_follow;index"`
+	Followee   User       `gorm:"foreignKey:FolloweeID;references:ID"`
+	CreatedAt  *time.Time `gorm:"autoCreateTime"`
+}
+
+type TagFollow struct {
+	ID        uint       `gorm:"primaryKey;not null"`
+	UserID    string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_tag_follow"`
+	TagID     uint       `gorm:"not null;uniqueIndex:idx_tag_follow;index"`
+	Tag       Tag        `gorm:"foreignKey:TagID;references:ID"`
+	CreatedAt *time.Time `gorm:"autoCreateTime"`
+}
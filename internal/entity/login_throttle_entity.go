@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+)
+
+type LoginThrottle struct {
+	ID           uint       `gorm:"primaryKey;not null"`
+	Scope        string     `gorm:"type:varchar(10);not null;uniqueIndex:idx_login_throttle_scope_identifier"`
+	Identifier   string     `gorm:"type:varchar(255);not null;uniqueIndex:idx_login_throttle_scope_identifier"`
+	Failures     int        `gorm:"not null;default:0"`
+	LastFailedAt *time.Time `gorm:"TIMESTAMP NULL"`
+	LockedUntil  *time.Time `gorm:"TIMESTAMP NULL"`
+	CreatedAt    *time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    *time.Time `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// Media is a file a user uploaded, URL is resolved by the storage when the file is stored.
+type Media struct {
+	ID          string         `gorm:"primaryKey;not null;type:varchar(36)"`
+	UserID      string         `gorm:"type:varchar(36);not null;index"`
+	StorageKey  string         `gorm:"type:varchar(255);not null"`
+	URL         string         `gorm:"type:varchar(500);not null"`
+	FileName    string         `gorm:"type:varchar(255);not null"`
+	ContentType string         `gorm:"type:varchar(50);not null"`
+	Size        int64          `gorm:"not null"`
+	Width       int            `gorm:"not null"`
+	Height      int            `gorm:"not null"`
+	Variants    []MediaVariant `gorm:"foreignKey:MediaID;references:ID"`
+	CreatedAt   *time.Time     `gorm:"autoCreateTime"`
+}
+
+// MediaVariant is a smaller copy of an image for responsive srcset.
+type MediaVariant struct {
+	ID          uint   `gorm:"primaryKey;not null"`
+	MediaID     string `gorm:"type:varchar(36);not null;index"`
+	StorageKey  string `gorm:"type:varchar(255);not null"`
+	URL         string `gorm:"type:varchar(500);not null"`
+	ContentType string `gorm:"type:varchar(50);not null"`
+	Size        int64  `gorm:"not null"`
+	Width       int    `gorm:"not null"`
+	Height      int    `gorm:"not null"`
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	NotificationTypePostPublished    = "post_published"
+	NotificationTypePostsTransferred = "posts_transferred"
+	NotificationTypeCoauthorInvited  = "coauthor_invited"
+	NotificationTypePostEdited       = "post_edited"
+	NotificationTypeAccountUpdated   = "account_updated"
+	NotificationTypeAccountDeleted   = "account_deleted"
+	NotificationTypeAccountRestored  = "account_restored"
+)
+
+// NotificationTypes lists every type users can set preferences for.
+var NotificationTypes = []string{
+	NotificationTypePostPublished,
+	NotificationTypePostsTransferred,
+	NotificationTypeCoauthorInvited,
+	NotificationTypePostEdited,
+	NotificationTypeAccountUpdated,
+	NotificationTypeAccountDeleted,
+	NotificationTypeAccountRestored,
+}
+
+// Notification is addressed to UserID, ActorID is empty when the system or an admin acted.
+// Link is a path relative to APP_BASE_URL.
+type Notification struct {
+	ID        uint       `gorm:"primaryKey;not null"`
+	UserID    string     `gorm:"type:varchar(36);not null;index:idx_notification_user_read,priority:1"`
+	Type      string     `gorm:"type:varchar(30);not null"`
+	ActorID   string     `gorm:"type:varchar(36)"`
+	Message   string     `gorm:"type:varchar(255);not null"`
+	Link      string     `gorm:"type:varchar(255)"`
+	ReadAt    *time.Time `gorm:"index:idx_notification_user_read,priority:2"`
+	EmailedAt *time.Time
+	CreatedAt *time.Time `gorm:"autoCreateTime"`
+}
+
+// NotificationPreference is only stored once a user changes the defaults, which are
+// enabled in app and no email. Email only applies to enabled types.
+type NotificationPreference struct {
+	UserID  string `gorm:"primaryKey;type:varchar(36);not null"`
+	Type    string `gorm:"primaryKey;type:varchar(30);not null"`
+	Enabled bool   `gorm:"not null"`
+	Email   bool   `gorm:"not null"`
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	PostAuthorRoleAuthor   = "author"
+	PostAuthorRoleEditor   = "editor"
+	PostAuthorRoleReviewer = "reviewer"
+)
+
+// PostAuthor credits a contributor of a post next to its primary author Post.UserID. The invitation is
+// pending until AcceptedAt is set, authors and editors may edit the post from then on, reviewers are only credited.
+type PostAuthor struct {
+	ID         uint       `gorm:"primaryKey;not null"`
+	PostID     uint       `gorm:"not null;uniqueIndex:idx_post_author"`
+	UserID     string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_post_author;index"`
+	User       User       `gorm:"foreignKey:UserID;references:ID"`
+	Post       Post       `gorm:"foreignKey:PostID;references:ID"`
+	Role       string     `gorm:"type:varchar(10);not null"`
+	InvitedBy  string     `gorm:"type:varchar(36);not null"`
+	AcceptedAt *time.Time `gorm:"TIMESTAMP NULL"`
+	CreatedAt  *time.Time `gorm:"autoCreateTime"`
+}
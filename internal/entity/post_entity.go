@@ -6,15 +6,28 @@ import (
 )
 
 type Post struct {
-	ID          uint           `gorm:"primaryKey;not null"`
-	Title       string         `gorm:"type:varchar(100);not null"`
-	Slug        string         `gorm:"type:varchar(255);not null"`
-	Content     string         `gorm:"type:longtext;not null"`
-	UserID      string         `gorm:"type:varchar(36)"`
-	Tags        []*Tag         `gorm:"many2many:post_tags"`
-	User        User           `gorm:"foreignKey:UserID;references:ID"`
-	PublishedAt *time.Time     `gorm:"TIMESTAMP NULL"`
-	CreatedAt   *time.Time     `gorm:"autoCreateTime"`
-	UpdatedAt   *time.Time     `gorm:"autoUpdateTime"`
-	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	ID              uint                `gorm:"primaryKey;not null"`
+	Title           string              `gorm:"type:varchar(100);not null"`
+	Slug            string              `gorm:"type:varchar(255);not null"`
+	Content         string              `gorm:"type:longtext;not null"`
+	UserID          string              `gorm:"type:varchar(36)"`
+	Tags            []*Tag              `gorm:"many2many:post_tags"`
+	User            User                `gorm:"foreignKey:UserID;references:ID"`
+	CommentCount    int64               `gorm:"not null;default:0"`
+	ReactionCounts  []PostReactionCount `gorm:"foreignKey:PostID;references:ID"`
+	MetaTitle       string              `gorm:"type:varchar(100)"`
+	MetaDescription string              `gorm:"type:varchar(300)"`
+	CanonicalURL    string              `gorm:"type:varchar(255)"`
+	SocialImage     string              `gorm:"type:varchar(255)"`
+	NoIndex         bool                `gorm:"not null;default:false"`
+	CoverMediaID    *string             `gorm:"type:varchar(36);index"`
+	CoverMedia      *Media              `gorm:"foreignKey:CoverMediaID;references:ID"`
+	CoverAlt        string              `gorm:"type:varchar(300)"`
+	CoverCaption    string              `gorm:"type:varchar(500)"`
+	Gallery         []PostImage         `gorm:"foreignKey:PostID;references:ID"`
+	Authors         []PostAuthor        `gorm:"foreignKey:PostID;references:ID"`
+	PublishedAt     *time.Time          `gorm:"TIMESTAMP NULL"`
+	CreatedAt       *time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt       *time.Time          `gorm:"autoUpdateTime"`
+	DeletedAt       gorm.DeletedAt      `gorm:"index"`
 }
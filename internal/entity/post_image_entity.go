@@ -0,0 +1,12 @@
+package entity
+
+// PostImage is an image of the gallery of a post, shown in Position order.
+type PostImage struct {
+	ID       uint   `gorm:"primaryKey;not null"`
+	PostID   uint   `gorm:"not null;index"`
+	MediaID  string `gorm:"type:varchar(36);not null;index"`
+	Media    Media  `gorm:"foreignKey:MediaID;references:ID"`
+	Position int    `gorm:"not null"`
+	Alt      string `gorm:"type:varchar(300);not null"`
+	Caption  string `gorm:"type:varchar(500)"`
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReactionTypes is the fixed set of reactions a user can leave on a post.
+var ReactionTypes = []string{"like", "love", "clap", "insightful", "laugh"}
+
+type Reaction struct {
+	ID        uint       `gorm:"primaryKey;not null"`
+	PostID    uint       `gorm:"not null;uniqueIndex:idx_reaction_post_user_type"`
+	UserID    string     `gorm:"type:varchar(36);not null;uniqueIndex:idx_reaction_post_user_type;index"`
+	Type      string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_reaction_post_user_type"`
+	CreatedAt *time.Time `gorm:"autoCreateTime"`
+}
+
+// PostReactionCount is maintained next to the reactions so listing posts never has to aggregate them.
+type PostReactionCount struct {
+	PostID uint   `gorm:"primaryKey;not null;autoIncrement:false"`
+	Type   string `gorm:"primaryKey;type:varchar(20);not null"`
+	Count  int64  `gorm:"not null;default:0"`
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	ReadingListVisibilityPrivate = "private"
+	ReadingListVisibilityPublic  = "public"
+)
+
+type ReadingList struct {
+	ID          uint              `gorm:"primaryKey;not null"`
+	UserID      string            `gorm:"type:varchar(36);not null;index"`
+	Name        string            `gorm:"type:varchar(100);not null"`
+	Description string            `gorm:"type:varchar(255)"`
+	Visibility  string            `gorm:"type:varchar(10);not null;default:private"`
+	Items       []ReadingListItem `gorm:"foreignKey:ReadingListID;references:ID"`
+	CreatedAt   *time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt   *time.Time        `gorm:"autoUpdateTime"`
+}
+
+// ReadingListItem keeps a post in a list, Position starts at 1 and orders the list.
+type ReadingListItem struct {
+	ID            uint       `gorm:"primaryKey;not null"`
+	ReadingListID uint       `gorm:"not null;uniqueIndex:idx_reading_list_post"`
+	PostID        uint       `gorm:"not null;uniqueIndex:idx_reading_list_post;index"`
+	Position      int        `gorm:"not null"`
+	Post          Post       `gorm:"foreignKey:PostID;references:ID"`
+	CreatedAt     *time.Time `gorm:"autoCreateTime"`
+}
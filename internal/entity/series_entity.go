@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+// Series links the posts of a multi-part article, the slug is unique and kept when the title changes.
+type Series struct {
+	ID          uint         `gorm:"primaryKey;not null"`
+	UserID      string       `gorm:"type:varchar(36);not null;index"`
+	User        User         `gorm:"foreignKey:UserID;references:ID"`
+	Title       string       `gorm:"type:varchar(100);not null"`
+	Slug        string       `gorm:"type:varchar(255);not null;uniqueIndex"`
+	Description string       `gorm:"type:varchar(500)"`
+	Items       []SeriesPost `gorm:"foreignKey:SeriesID;references:ID"`
+	CreatedAt   *time.Time   `gorm:"autoCreateTime"`
+	UpdatedAt   *time.Time   `gorm:"autoUpdateTime"`
+}
+
+// SeriesPost makes a post a part of a series, Position starts at 1. A post is part of one series at most.
+type SeriesPost struct {
+	ID        uint       `gorm:"primaryKey;not null"`
+	SeriesID  uint       `gorm:"not null;index"`
+	PostID    uint       `gorm:"not null;uniqueIndex"`
+	Position  int        `gorm:"not null"`
+	Post      Post       `gorm:"foreignKey:PostID;references:ID"`
+	CreatedAt *time.Time `gorm:"autoCreateTime"`
+}
@@ -11,8 +11,17 @@ type User struct {
 	Username  string `gorm:"not null;type:varchar(30);unique"`
 	Email     string `gorm:"not null;type:varchar(255);unique"`
 	Password  string `gorm:"not null;type:varchar(255)"`
-	Posts     []Post `gorm:"foreignKey:UserID;references:ID"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	Bio       string `gorm:"type:varchar(500)"`
+	AvatarURL string `gorm:"type:varchar(255)"`
+	Website   string `gorm:"type:varchar(255)"`
+	Twitter   string `gorm:"type:varchar(100)"`
+	Github    string `gorm:"type:varchar(100)"`
+	Linkedin  string `gorm:"type:varchar(100)"`
+	Mastodon  string `gorm:"type:varchar(100)"`
+	// IsPlaceholder marks the system user anonymized posts are attached to.
+	IsPlaceholder bool   `gorm:"not null;default:false;index"`
+	Posts         []Post `gorm:"foreignKey:UserID;references:ID"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
 }
@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"time"
+)
+
+type UsernameHistory struct {
+	ID          uint       `gorm:"primaryKey;not null"`
+	UserID      string     `gorm:"type:varchar(36);not null;index"`
+	OldUsername string     `gorm:"type:varchar(30);not null;index"`
+	User        User       `gorm:"foreignKey:UserID;references:ID"`
+	CreatedAt   *time.Time `gorm:"autoCreateTime"`
+}
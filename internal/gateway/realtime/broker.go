@@ -0,0 +1,53 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// Broker carries events between the hubs of every instance. LocalBroker is enough for a single
+// process, deployments with several instances plug in one backed by a shared pub/sub (Redis,
+// NATS, ...) so that an event published on one instance reaches the clients of all of them.
+type Broker interface {
+	Publish(ctx context.Context, event *Event) error
+	// Subscribe calls handler for every event published on any instance, until ctx is done.
+	Subscribe(ctx context.Context, handler func(event *Event)) error
+}
+
+// LocalBroker delivers the events to the handlers of this process only.
+type LocalBroker struct {
+	mu       sync.RWMutex
+	handlers map[int]func(event *Event)
+	nextId   int
+}
+
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{
+		handlers: make(map[int]func(event *Event)),
+	}
+}
+
+func (b *LocalBroker) Publish(ctx context.Context, event *Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (b *LocalBroker) Subscribe(ctx context.Context, handler func(event *Event)) error {
+	b.mu.Lock()
+	id := b.nextId
+	b.nextId++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}()
+	return nil
+}
@@ -0,0 +1,167 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	TopicPosts = "posts"
+)
+
+// TopicPost is the topic of the changes on one post.
+func TopicPost(slug string) string {
+	return "post:" + slug
+}
+
+// TopicUser is the topic of the changes on one account, only its owner may subscribe to it.
+func TopicUser(userId string) string {
+	return "user:" + userId
+}
+
+// Event ids are unix nanoseconds made strictly increasing per instance, so they order events
+// across instances as far as their clocks agree and a client can resume on any of them.
+type Event struct {
+	ID    uint64          `json:"id"`
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Publisher is what use cases need to emit events.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, eventType string, data any) error
+}
+
+// Client receives the events of its topics on Events, the channel is closed when the client
+// is too slow to keep up, it then has to reconnect and resume with its last event id.
+type Client struct {
+	Events chan *Event
+	topics map[string]bool
+}
+
+// Hub fans the events out to the clients of this instance and keeps the latest ones so a
+// reconnecting client can resume with Last-Event-ID.
+type Hub struct {
+	Log          *logrus.Logger
+	broker       Broker
+	clientBuffer int
+
+	mu      sync.RWMutex
+	clients map[*Client]struct{}
+	buffer  []*Event
+	next    int
+	full    bool
+
+	idMu   sync.Mutex
+	lastId uint64
+}
+
+func NewHub(log *logrus.Logger, broker Broker, bufferSize int, clientBuffer int) *Hub {
+	return &Hub{
+		Log:          log,
+		broker:       broker,
+		clientBuffer: clientBuffer,
+		clients:      make(map[*Client]struct{}),
+		buffer:       make([]*Event, bufferSize),
+	}
+}
+
+// Run receives the events of the broker until ctx is done.
+func (h *Hub) Run(ctx context.Context) error {
+	return h.broker.Subscribe(ctx, h.dispatch)
+}
+
+func (h *Hub) Publish(ctx context.Context, topic string, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return h.broker.Publish(ctx, &Event{
+		ID:    h.nextEventId(),
+		Topic: topic,
+		Type:  eventType,
+		Data:  payload,
+	})
+}
+
+// Subscribe registers a client for topics. When lastEventId is set, the buffered events after it
+// are returned to be sent first, registering and collecting them at once means none is lost or doubled.
+func (h *Hub) Subscribe(topics []string, lastEventId uint64) (*Client, []*Event) {
+	client := &Client{
+		Events: make(chan *Event, h.clientBuffer),
+		topics: make(map[string]bool, len(topics)),
+	}
+	for _, topic := range topics {
+		client.topics[topic] = true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.clients[client] = struct{}{}
+
+	var replay []*Event
+	if lastEventId > 0 {
+		for _, event := range h.buffered() {
+			if event.ID > lastEventId && client.topics[event.Topic] {
+				replay = append(replay, event)
+			}
+		}
+	}
+	return client, replay
+}
+
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		close(client.Events)
+	}
+}
+
+func (h *Hub) dispatch(event *Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.buffer) > 0 {
+		h.buffer[h.next] = event
+		h.next = (h.next + 1) % len(h.buffer)
+		h.full = h.full || h.next == 0
+	}
+
+	for client := range h.clients {
+		if !client.topics[event.Topic] {
+			continue
+		}
+		select {
+		case client.Events <- event:
+		default:
+			h.Log.Warnf("Dropping slow event stream client")
+			delete(h.clients, client)
+			close(client.Events)
+		}
+	}
+}
+
+// buffered returns the buffer oldest first, the caller holds the lock.
+func (h *Hub) buffered() []*Event {
+	if !h.full {
+		return h.buffer[:h.next]
+	}
+	return append(append([]*Event{}, h.buffer[h.next:]...), h.buffer[:h.next]...)
+}
+
+func (h *Hub) nextEventId() uint64 {
+	h.idMu.Lock()
+	defer h.idMu.Unlock()
+	id := uint64(time.Now().UnixNano())
+	if id <= h.lastId {
+		id = h.lastId + 1
+	}
+	h.lastId = id
+	return id
+}
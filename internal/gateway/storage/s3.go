@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Storage talks to an S3 compatible API such as AWS S3, MinIO or R2, the requests are signed with
+// AWS Signature Version 4. The bucket must allow public reads of the objects, or PublicURL must point
+// to something that serves them such as a CDN.
+type S3Storage struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+	// PathStyle puts the bucket in the path instead of the host name, MinIO needs it.
+	PathStyle bool
+	Client    *http.Client
+}
+
+func NewS3Storage(endpoint string, region string, bucket string, accessKey string, secretKey string, publicURL string, pathStyle bool) *S3Storage {
+	return &S3Storage{
+		Endpoint:  strings.TrimRight(endpoint, "/"),
+		Region:    region,
+		Bucket:    bucket,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		PublicURL: strings.TrimRight(publicURL, "/"),
+		PathStyle: pathStyle,
+		Client:    &http.Client{Timeout: time.Minute},
+	}
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, contentType string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", contentType)
+	// Keys are never reused, so the objects can be cached for good.
+	request.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	return s.do(request, body, http.StatusOK)
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(request, nil, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (s *S3Storage) URL(key string) string {
+	if s.PublicURL != "" {
+		return s.PublicURL + "/" + escapePath(key)
+	}
+	return s.objectURL(key)
+}
+
+func (s *S3Storage) objectURL(key string) string {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil || s.PathStyle {
+		return s.Endpoint + "/" + s.Bucket + "/" + escapePath(key)
+	}
+	return endpoint.Scheme + "://" + s.Bucket + "." + endpoint.Host + "/" + escapePath(key)
+}
+
+func (s *S3Storage) do(request *http.Request, body []byte, expected ...int) error {
+	s.sign(request, body, time.Now().UTC())
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	for _, status := range expected {
+		if response.StatusCode == status {
+			return nil
+		}
+	}
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s: %s", request.Method, request.URL.Path, response.Status, strings.TrimSpace(string(message)))
+}
+
+// sign adds the Signature Version 4 headers, every header already set on the request is signed.
+func (s *S3Storage) sign(request *http.Request, body []byte, now time.Time) {
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	request.Header.Set("Host", request.URL.Host)
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	names := make([]string, 0, len(request.Header))
+	for name := range request.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	var headers strings.Builder
+	for _, name := range names {
+		headers.WriteString(name + ":" + strings.TrimSpace(request.Header.Get(name)) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalSum := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalSum[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKey, scope, signedHeaders, signature))
+	// net/http sends the host from the URL, the header was only needed for the signature.
+	request.Header.Del("Host")
+}
+
+func hmacSHA256(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// escapePath escapes every segment of key the way S3 expects in the canonical request.
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,13 @@
+package storage
+
+import (
+	"context"
+)
+
+// Storage keeps the uploaded files, keys are slash separated paths such as 2024/05/<id>.png.
+type Storage interface {
+	Put(ctx context.Context, key string, contentType string, body []byte) error
+	Delete(ctx context.Context, key string) error
+	// URL is the public address the file is served from.
+	URL(key string) string
+}
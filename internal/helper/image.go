@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+// ErrUnsupportedImage is returned for content that is not one of ImageTypes, whatever its file name says.
+var ErrUnsupportedImage = errors.New("unsupported image type")
+
+// ImageTypes are the accepted image types with the extension their files are stored with. SVG is left
+// out on purpose, it can carry scripts.
+var ImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// SniffImage detects the type of body from its content and reads the dimensions from its header.
+func SniffImage(body []byte) (contentType string, width int, height int, err error) {
+	contentType = http.DetectContentType(body)
+	if _, ok := ImageTypes[contentType]; !ok {
+		return "", 0, 0, ErrUnsupportedImage
+	}
+
+	if contentType == "image/webp" {
+		width, height, err = webpSize(body)
+		return contentType, width, height, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return "", 0, 0, ErrUnsupportedImage
+	}
+	return contentType, config.Width, config.Height, nil
+}
+
+// webpSize reads the canvas size of a lossy, lossless or extended WebP, the standard library has no decoder.
+func webpSize(body []byte) (int, int, error) {
+	if len(body) < 30 {
+		return 0, 0, ErrUnsupportedImage
+	}
+	data := body[20:]
+	switch string(body[12:16]) {
+	case "VP8 ":
+		if !bytes.Equal(data[3:6], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0, ErrUnsupportedImage
+		}
+		return int(binary.LittleEndian.Uint16(data[6:8]) & 0x3fff), int(binary.LittleEndian.Uint16(data[8:10]) & 0x3fff), nil
+	case "VP8L":
+		if data[0] != 0x2f {
+			return 0, 0, ErrUnsupportedImage
+		}
+		bits := binary.LittleEndian.Uint32(data[1:5])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, nil
+	case "VP8X":
+		width := int(data[4]) | int(data[5])<<8 | int(data[6])<<16
+		height := int(data[7]) | int(data[8])<<8 | int(data[9])<<16
+		return width + 1, height + 1, nil
+	}
+	return 0, 0, ErrUnsupportedImage
+}
@@ -0,0 +1,188 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrImageMetadata is returned by StripImageMetadata when the file cannot be parsed, the metadata it
+// may hold is then still there.
+var ErrImageMetadata = errors.New("image metadata cannot be stripped")
+
+// StripImageMetadata drops the EXIF, XMP, IPTC and text metadata of a JPEG, PNG or WebP without
+// re-encoding it, such as the GPS position a phone records. Color profiles are kept. GIF has no
+// such metadata and is returned as is. A file that fails to parse returns ErrImageMetadata, its
+// original bytes must not be stored.
+func StripImageMetadata(contentType string, body []byte) ([]byte, error) {
+	var stripped []byte
+	switch contentType {
+	case "image/jpeg":
+		stripped = stripJPEG(body)
+	case "image/png":
+		stripped = stripPNG(body)
+	case "image/webp":
+		stripped = stripWebP(body)
+	default:
+		return body, nil
+	}
+	if stripped == nil {
+		return nil, ErrImageMetadata
+	}
+	return stripped, nil
+}
+
+// ImageOrientation returns the EXIF orientation of a JPEG, 1 when it has none. Stripping the metadata
+// loses it, so an image with another orientation has to be rotated first.
+func ImageOrientation(body []byte) int {
+	for _, segment := range jpegSegments(body) {
+		if segment.marker != 0xe1 || !bytes.HasPrefix(segment.data, []byte("Exif\x00\x00")) {
+			continue
+		}
+		tiff := segment.data[6:]
+		if len(tiff) < 8 {
+			return 1
+		}
+		var order binary.ByteOrder = binary.BigEndian
+		if string(tiff[:2]) == "II" {
+			order = binary.LittleEndian
+		}
+		ifd := int(order.Uint32(tiff[4:8]))
+		if ifd < 0 || ifd+2 > len(tiff) {
+			return 1
+		}
+		count := int(order.Uint16(tiff[ifd:]))
+		for i := 0; i < count; i++ {
+			entry := ifd + 2 + i*12
+			if entry+12 > len(tiff) {
+				break
+			}
+			if order.Uint16(tiff[entry:]) == 0x0112 {
+				if orientation := int(order.Uint16(tiff[entry+8:])); orientation >= 1 && orientation <= 8 {
+					return orientation
+				}
+			}
+		}
+		return 1
+	}
+	return 1
+}
+
+type jpegSegment struct {
+	marker byte
+	// raw is the whole segment with its marker, data the payload after the length.
+	raw  []byte
+	data []byte
+}
+
+// jpegSegments splits the header segments of a JPEG up to the start of scan, nil when it is malformed.
+func jpegSegments(body []byte) []jpegSegment {
+	if len(body) < 4 || body[0] != 0xff || body[1] != 0xd8 {
+		return nil
+	}
+	var segments []jpegSegment
+	for i := 2; i+4 <= len(body); {
+		if body[i] != 0xff {
+			return nil
+		}
+		marker := body[i+1]
+		if marker == 0xda {
+			return segments
+		}
+		length := int(binary.BigEndian.Uint16(body[i+2:]))
+		if length < 2 || i+2+length > len(body) {
+			return nil
+		}
+		segments = append(segments, jpegSegment{
+			marker: marker,
+			raw:    body[i : i+2+length],
+			data:   body[i+4 : i+2+length],
+		})
+		i += 2 + length
+	}
+	return nil
+}
+
+func stripJPEG(body []byte) []byte {
+	segments := jpegSegments(body)
+	if segments == nil {
+		return nil
+	}
+
+	out := make([]byte, 0, len(body))
+	out = append(out, 0xff, 0xd8)
+	offset := 2
+	for _, segment := range segments {
+		offset += len(segment.raw)
+		// APP0 (JFIF), APP2 (ICC profile) and APP14 (Adobe color transform) are needed to show the image right.
+		if segment.marker >= 0xe1 && segment.marker <= 0xef && segment.marker != 0xe2 && segment.marker != 0xee {
+			continue
+		}
+		if segment.marker == 0xfe {
+			continue
+		}
+		out = append(out, segment.raw...)
+	}
+	return append(out, body[offset:]...)
+}
+
+func stripPNG(body []byte) []byte {
+	const signature = "\x89PNG\r\n\x1a\n"
+	if !bytes.HasPrefix(body, []byte(signature)) {
+		return nil
+	}
+
+	out := make([]byte, 0, len(body))
+	out = append(out, signature...)
+	for i := len(signature); i < len(body); {
+		if i+12 > len(body) {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint32(body[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(body) {
+			return nil
+		}
+		switch string(body[i+4 : i+8]) {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out = append(out, body[i:end]...)
+		}
+		i = end
+	}
+	return out
+}
+
+func stripWebP(body []byte) []byte {
+	if len(body) < 12 || string(body[:4]) != "RIFF" || string(body[8:12]) != "WEBP" {
+		return nil
+	}
+
+	out := make([]byte, 12, len(body))
+	copy(out, body[:12])
+	for i := 12; i < len(body); {
+		if i+8 > len(body) {
+			return nil
+		}
+		size := int(binary.LittleEndian.Uint32(body[i+4:]))
+		end := i + 8 + size + size%2
+		if size < 0 || end > len(body) {
+			return nil
+		}
+		switch fourcc := string(body[i : i+4]); fourcc {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), body[i:end]...)
+			// Clear the flags announcing the EXIF and XMP chunks.
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, body[i:end]...)
+		}
+		i = end
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out
+}
@@ -0,0 +1,122 @@
+package helper
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// DecodeImage decodes a JPEG, PNG or GIF (its first frame) and turns it upright as its EXIF orientation says.
+func DecodeImage(body []byte) (*image.RGBA, error) {
+	src, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, src.Bounds().Dx(), src.Bounds().Dy()))
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+	return orient(img, ImageOrientation(body)), nil
+}
+
+// orient applies one of the 8 EXIF orientations, 5 to 8 swap the width and the height.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+	return dst
+}
+
+// ResizeImage scales src down to width keeping its aspect ratio. Every target pixel is the average of
+// the source pixels it covers, which keeps downscaled photos free of aliasing.
+func ResizeImage(src *image.RGBA, width int) *image.RGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	height := max(sh*width/sw, 1)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	xStart := make([]int, width+1)
+	for x := range xStart {
+		xStart[x] = x * sw / width
+	}
+
+	for y := 0; y < height; y++ {
+		sy0 := y * sh / height
+		sy1 := max((y+1)*sh/height, sy0+1)
+		for x := 0; x < width; x++ {
+			sx0, sx1 := xStart[x], max(xStart[x+1], xStart[x]+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				row := src.Pix[src.PixOffset(sx0, sy):src.PixOffset(sx1, sy)]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					b += uint64(row[i+2])
+					a += uint64(row[i+3])
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// EncodeImage writes img as a PNG when it has transparency and as a JPEG of quality otherwise. The
+// encoders write no metadata.
+func EncodeImage(img *image.RGBA, quality int) ([]byte, string, error) {
+	var body bytes.Buffer
+	if !img.Opaque() {
+		err := png.Encode(&body, img)
+		return body.Bytes(), "image/png", err
+	}
+	err := jpeg.Encode(&body, img, &jpeg.Options{Quality: quality})
+	return body.Bytes(), "image/jpeg", err
+}
+
+// ReencodeImage writes img again as a JPEG of quality or as a PNG, keeping contentType. Nothing of the
+// original file but its pixels survives, which is the way out when its metadata cannot be stripped.
+func ReencodeImage(img *image.RGBA, contentType string, quality int) ([]byte, error) {
+	var body bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = png.Encode(&body, img)
+	} else {
+		err = jpeg.Encode(&body, img, &jpeg.Options{Quality: quality})
+	}
+	return body.Bytes(), err
+}
@@ -0,0 +1,136 @@
+package helper
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestResizeImageSizes(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		target        int
+		wantHeight    int
+	}{
+		{"same width", 40, 30, 40, 30},
+		{"half", 40, 30, 20, 15},
+		{"one pixel wide", 40, 30, 1, 1},
+		{"rounds down", 33, 10, 10, 3},
+		{"keeps at least one row", 300, 2, 10, 1},
+		{"tall", 3, 500, 2, 333},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resized := ResizeImage(image.NewRGBA(image.Rect(0, 0, test.width, test.height)), test.target)
+			if resized.Rect.Dx() != test.target || resized.Rect.Dy() != test.wantHeight {
+				t.Fatalf("size %dx%d, want %dx%d", resized.Rect.Dx(), resized.Rect.Dy(), test.target, test.wantHeight)
+			}
+		})
+	}
+}
+
+func TestResizeImageAverages(t *testing.T) {
+	// Left half black, right half white.
+	src := fillImage(4, 2, func(x, y int) color.Color {
+		if x < 2 {
+			return color.RGBA{0, 0, 0, 255}
+		}
+		return color.RGBA{255, 255, 255, 255}
+	})
+
+	same := ResizeImage(src, 4)
+	if !bytes.Equal(same.Pix, src.Pix) {
+		t.Fatal("resizing to the same width changed the pixels")
+	}
+
+	halves := ResizeImage(src, 2)
+	if got := halves.RGBAAt(0, 0); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Fatalf("left pixel %v, want black", got)
+	}
+	if got := halves.RGBAAt(1, 0); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Fatalf("right pixel %v, want white", got)
+	}
+
+	single := ResizeImage(src, 1)
+	if got := single.RGBAAt(0, 0); got != (color.RGBA{127, 127, 127, 255}) {
+		t.Fatalf("pixel %v, want the average gray", got)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image whose top left pixel is red and top right pixel is blue, the rest green.
+	red, blue, green := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}, color.RGBA{0, 255, 0, 255}
+	src := fillImage(3, 2, func(x, y int) color.Color {
+		switch {
+		case x == 0 && y == 0:
+			return red
+		case x == 2 && y == 0:
+			return blue
+		}
+		return green
+	})
+
+	tests := []struct {
+		orientation   int
+		width, height int
+		red, blue     image.Point
+	}{
+		{1, 3, 2, image.Pt(0, 0), image.Pt(2, 0)},
+		{2, 3, 2, image.Pt(2, 0), image.Pt(0, 0)},
+		{3, 3, 2, image.Pt(2, 1), image.Pt(0, 1)},
+		{4, 3, 2, image.Pt(0, 1), image.Pt(2, 1)},
+		{5, 2, 3, image.Pt(0, 0), image.Pt(0, 2)},
+		{6, 2, 3, image.Pt(1, 0), image.Pt(1, 2)},
+		{7, 2, 3, image.Pt(1, 2), image.Pt(1, 0)},
+		{8, 2, 3, image.Pt(0, 2), image.Pt(0, 0)},
+		{9, 3, 2, image.Pt(0, 0), image.Pt(2, 0)},
+	}
+	for _, test := range tests {
+		dst := orient(src, test.orientation)
+		if dst.Rect.Dx() != test.width || dst.Rect.Dy() != test.height {
+			t.Fatalf("orientation %d: size %dx%d, want %dx%d", test.orientation, dst.Rect.Dx(), dst.Rect.Dy(), test.width, test.height)
+		}
+		if got := dst.RGBAAt(test.red.X, test.red.Y); got != red {
+			t.Fatalf("orientation %d: pixel at %v is %v, want red", test.orientation, test.red, got)
+		}
+		if got := dst.RGBAAt(test.blue.X, test.blue.Y); got != blue {
+			t.Fatalf("orientation %d: pixel at %v is %v, want blue", test.orientation, test.blue, got)
+		}
+	}
+}
+
+func TestDecodeImageAppliesExifOrientation(t *testing.T) {
+	var encoded bytes.Buffer
+	src := fillImage(32, 16, func(x, y int) color.Color {
+		if x < 16 {
+			return color.RGBA{255, 0, 0, 255}
+		}
+		return color.RGBA{0, 0, 255, 255}
+	})
+	if err := jpeg.Encode(&encoded, src, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An APP1 segment with a big endian TIFF header and a single orientation entry of 6, rotate 90° clockwise.
+	exif := []byte("Exif\x00\x00MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00\x00\x00\x00\x00")
+	segment := append([]byte{0xff, 0xe1, byte((len(exif) + 2) >> 8), byte(len(exif) + 2)}, exif...)
+	body := append(append([]byte{0xff, 0xd8}, segment...), encoded.Bytes()[2:]...)
+
+	if orientation := ImageOrientation(body); orientation != 6 {
+		t.Fatalf("ImageOrientation = %d, want 6", orientation)
+	}
+	img, err := DecodeImage(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Rect.Dx() != 16 || img.Rect.Dy() != 32 {
+		t.Fatalf("size %dx%d, want the rotated 16x32", img.Rect.Dx(), img.Rect.Dy())
+	}
+	// The red left half ends up on top.
+	if top, bottom := img.RGBAAt(8, 4), img.RGBAAt(8, 28); top.R < 200 || bottom.B < 200 {
+		t.Fatalf("top %v and bottom %v, want red above blue", top, bottom)
+	}
+}
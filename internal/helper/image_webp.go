@@ -0,0 +1,631 @@
+package helper
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"sort"
+)
+
+// webpPredictorBits sets the predictor tiles to 16x16 pixels, each tile picks its own predictor.
+const webpPredictorBits = 4
+
+const (
+	webpMaxLength   = 4096
+	webpMaxDistance = 1<<20 - 120
+	webpMaxChain    = 32
+	webpHashBits    = 16
+)
+
+// webpCodeLengthOrder is the order the code lengths of the code length code are written in.
+var webpCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// webpToken is a literal ARGB pixel, or a copy of length pixels from distance pixels back when length is set.
+type webpToken struct {
+	argb     uint32
+	length   int
+	distance int
+}
+
+// EncodeWebP writes img as a lossless WebP (VP8L), which needs no library outside the standard one.
+// The pixels go through the subtract green and predictor transforms and are then LZ77 and Huffman
+// coded. No metadata is written.
+func EncodeWebP(img *image.RGBA) ([]byte, error) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if w < 1 || h < 1 || w > 1<<14 || h > 1<<14 {
+		return nil, errors.New("webp: image dimensions out of range")
+	}
+
+	pix := make([]uint32, 0, w*h)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(img.Rect.Min.X, y):img.PixOffset(img.Rect.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			pix = append(pix, webpARGB(row[i], row[i+1], row[i+2], row[i+3]))
+		}
+	}
+
+	var bw webpBitWriter
+	bw.write(0x2f, 8)
+	bw.write(uint32(w-1), 14)
+	bw.write(uint32(h-1), 14)
+	if img.Opaque() {
+		bw.write(0, 1)
+	} else {
+		bw.write(1, 1)
+	}
+	bw.write(0, 3)
+
+	// The decoder undoes the transforms in the reverse order they are written.
+	webpSubtractGreen(pix)
+	bw.write(1, 1)
+	bw.write(2, 2)
+
+	residuals, modes, tilesW, tilesH := webpPredict(pix, w, h)
+	bw.write(1, 1)
+	bw.write(0, 2)
+	bw.write(webpPredictorBits-2, 3)
+	webpWriteImage(&bw, modes, tilesW, tilesH, false)
+
+	bw.write(0, 1)
+	webpWriteImage(&bw, residuals, w, h, true)
+
+	data := bw.bytes()
+	padding := len(data) & 1
+	out := make([]byte, 0, 20+len(data)+padding)
+	out = append(out, "RIFF"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(12+len(data)+padding))
+	out = append(out, "WEBPVP8L"...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(data)))
+	out = append(out, data...)
+	if padding == 1 {
+		out = append(out, 0)
+	}
+	return out, nil
+}
+
+// webpARGB packs a pixel of an image.RGBA, whose colors are alpha premultiplied, as the straight ARGB
+// WebP stores.
+func webpARGB(r, g, b, a uint8) uint32 {
+	if a == 0 {
+		return 0
+	}
+	if a < 0xff {
+		unpremultiply := func(c uint8) uint32 {
+			return (uint32(c) * 0x101 * 0xffff / (uint32(a) * 0x101)) >> 8
+		}
+		return uint32(a)<<24 | unpremultiply(r)<<16 | unpremultiply(g)<<8 | unpremultiply(b)
+	}
+	return uint32(a)<<24 | uint32(r)<<16 | uint32(g)<<8 | uint32(b)
+}
+
+func webpSubtractGreen(pix []uint32) {
+	for i, p := range pix {
+		green := (p >> 8) & 0xff
+		red := ((p >> 16) - green) & 0xff
+		blue := (p - green) & 0xff
+		pix[i] = p&0xff00ff00 | red<<16 | blue
+	}
+}
+
+// webpPredict picks for every tile the predictor leaving the smallest residuals and returns the
+// residuals with the tile predictors, kept in the green channel of a tiles wide image.
+func webpPredict(pix []uint32, w, h int) ([]uint32, []uint32, int, int) {
+	tileSize := 1 << webpPredictorBits
+	tilesW := (w + tileSize - 1) >> webpPredictorBits
+	tilesH := (h + tileSize - 1) >> webpPredictorBits
+	modes := make([]uint32, tilesW*tilesH)
+
+	for ty := 0; ty < tilesH; ty++ {
+		for tx := 0; tx < tilesW; tx++ {
+			x0, y0 := max(tx*tileSize, 1), max(ty*tileSize, 1)
+			x1, y1 := min((tx+1)*tileSize, w), min((ty+1)*tileSize, h)
+
+			best, bestCost := 0, -1
+			for mode := 0; mode < 14; mode++ {
+				cost := 0
+				for y := y0; y < y1 && (bestCost < 0 || cost < bestCost); y++ {
+					for x := x0; x < x1; x++ {
+						i := y*w + x
+						cost += webpResidualCost(webpSub(pix[i], webpPredictor(mode, pix, i, w)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+			modes[ty*tilesW+tx] = 0xff000000 | uint32(best)<<8
+		}
+	}
+
+	residuals := make([]uint32, len(pix))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := y*w + x
+			var predicted uint32
+			switch {
+			case x == 0 && y == 0:
+				predicted = 0xff000000
+			case y == 0:
+				predicted = pix[i-1]
+			case x == 0:
+				predicted = pix[i-w]
+			default:
+				mode := (modes[(y>>webpPredictorBits)*tilesW+x>>webpPredictorBits] >> 8) & 0xff
+				predicted = webpPredictor(int(mode), pix, i, w)
+			}
+			residuals[i] = webpSub(pix[i], predicted)
+		}
+	}
+	return residuals, modes, tilesW, tilesH
+}
+
+// webpPredictor is one of the 14 predictors of the pixel at i, which has a left and a top neighbour.
+// The top right neighbour of the last column is the first pixel of the row, as in the decoder.
+func webpPredictor(mode int, pix []uint32, i int, w int) uint32 {
+	left, top, topLeft, topRight := pix[i-1], pix[i-w], pix[i-w-1], pix[i-w+1]
+	switch mode {
+	case 0:
+		return 0xff000000
+	case 1:
+		return left
+	case 2:
+		return top
+	case 3:
+		return topRight
+	case 4:
+		return topLeft
+	case 5:
+		return webpAverage(webpAverage(left, topRight), top)
+	case 6:
+		return webpAverage(left, topLeft)
+	case 7:
+		return webpAverage(left, top)
+	case 8:
+		return webpAverage(topLeft, top)
+	case 9:
+		return webpAverage(top, topRight)
+	case 10:
+		return webpAverage(webpAverage(left, topLeft), webpAverage(top, topRight))
+	case 11:
+		return webpSelect(left, top, topLeft)
+	case 12:
+		return webpChannels(func(shift uint) uint32 {
+			return webpClamp(int(left>>shift&0xff) + int(top>>shift&0xff) - int(topLeft>>shift&0xff))
+		})
+	default:
+		average := webpAverage(left, top)
+		return webpChannels(func(shift uint) uint32 {
+			a := int(average >> shift & 0xff)
+			return webpClamp(a + (a-int(topLeft>>shift&0xff))/2)
+		})
+	}
+}
+
+func webpChannels(channel func(shift uint) uint32) uint32 {
+	return channel(24)<<24 | channel(16)<<16 | channel(8)<<8 | channel(0)
+}
+
+func webpClamp(value int) uint32 {
+	return uint32(min(max(value, 0), 0xff))
+}
+
+// webpAverage halves the sum of every channel, rounding down.
+func webpAverage(a, b uint32) uint32 {
+	return ((a^b)&0xfefefefe)>>1 + a&b
+}
+
+// webpSelect returns left or top, whichever is closer to left + top - topLeft.
+func webpSelect(left, top, topLeft uint32) uint32 {
+	distanceLeft, distanceTop := 0, 0
+	for shift := uint(0); shift < 32; shift += 8 {
+		l, t, tl := int(left>>shift&0xff), int(top>>shift&0xff), int(topLeft>>shift&0xff)
+		distanceLeft += webpAbs(t - tl)
+		distanceTop += webpAbs(l - tl)
+	}
+	if distanceLeft < distanceTop {
+		return left
+	}
+	return top
+}
+
+// webpSub subtracts every channel of b from a, modulo 256.
+func webpSub(a, b uint32) uint32 {
+	alphaGreen := 0x00ff00ff + a&0xff00ff00 - b&0xff00ff00
+	redBlue := 0xff00ff00 + a&0x00ff00ff - b&0x00ff00ff
+	return alphaGreen&0xff00ff00 | redBlue&0x00ff00ff
+}
+
+// webpResidualCost estimates how costly a residual is to code, small values either way are cheap.
+func webpResidualCost(residual uint32) int {
+	cost := 0
+	for shift := uint(0); shift < 32; shift += 8 {
+		c := int(residual >> shift & 0xff)
+		cost += min(c, 0x100-c)
+	}
+	return cost
+}
+
+func webpAbs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// webpWriteImage entropy codes pix with a single group of prefix codes and no color cache.
+func webpWriteImage(bw *webpBitWriter, pix []uint32, w, h int, top bool) {
+	tokens := webpBackwardReferences(pix, w)
+
+	bw.write(0, 1)
+	if top {
+		bw.write(0, 1)
+	}
+
+	histograms := [5][]int{make([]int, 256+24), make([]int, 256), make([]int, 256), make([]int, 256), make([]int, 40)}
+	for _, token := range tokens {
+		if token.length == 0 {
+			histograms[0][token.argb>>8&0xff]++
+			histograms[1][token.argb>>16&0xff]++
+			histograms[2][token.argb&0xff]++
+			histograms[3][token.argb>>24]++
+			continue
+		}
+		lengthCode, _, _ := webpPrefix(token.length)
+		distanceCode, _, _ := webpPrefix(webpDistanceCode(token.distance, w))
+		histograms[0][256+lengthCode]++
+		histograms[4][distanceCode]++
+	}
+
+	var codes [5]webpPrefixCode
+	for i, histogram := range histograms {
+		codes[i] = webpWritePrefixCode(bw, histogram)
+	}
+
+	for _, token := range tokens {
+		if token.length == 0 {
+			codes[0].write(bw, int(token.argb>>8&0xff))
+			codes[1].write(bw, int(token.argb>>16&0xff))
+			codes[2].write(bw, int(token.argb&0xff))
+			codes[3].write(bw, int(token.argb>>24))
+			continue
+		}
+		lengthCode, lengthBits, lengthExtra := webpPrefix(token.length)
+		codes[0].write(bw, 256+lengthCode)
+		bw.write(lengthExtra, lengthBits)
+		distanceCode, distanceBits, distanceExtra := webpPrefix(webpDistanceCode(token.distance, w))
+		codes[4].write(bw, distanceCode)
+		bw.write(distanceExtra, distanceBits)
+	}
+}
+
+// webpBackwardReferences finds repeated runs of pixels with hash chains, greedily taking the longest
+// match of at least 3 pixels.
+func webpBackwardReferences(pix []uint32, w int) []webpToken {
+	head := make([]int32, 1<<webpHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	chain := make([]int32, len(pix))
+	hash := func(i int) uint32 {
+		return (pix[i]*0x1e35a7bd ^ pix[i+1]*0x9e3779b1) >> (32 - webpHashBits)
+	}
+	insert := func(i int) {
+		if i+1 < len(pix) {
+			key := hash(i)
+			chain[i] = head[key]
+			head[key] = int32(i)
+		}
+	}
+	matchLength := func(i, candidate int) int {
+		limit := min(webpMaxLength, len(pix)-i)
+		length := 0
+		for length < limit && pix[candidate+length] == pix[i+length] {
+			length++
+		}
+		return length
+	}
+
+	tokens := make([]webpToken, 0, len(pix)/2)
+	for i := 0; i < len(pix); {
+		bestLength, bestDistance := 0, 0
+		if i+1 < len(pix) {
+			// The pixel to the left and the one above have the cheapest distance codes.
+			for _, distance := range [2]int{1, w} {
+				if distance <= i {
+					if length := matchLength(i, i-distance); length > bestLength {
+						bestLength, bestDistance = length, distance
+					}
+				}
+			}
+			candidate := head[hash(i)]
+			for steps := 0; candidate >= 0 && steps < webpMaxChain && bestLength < webpMaxLength; steps++ {
+				if i-int(candidate) > webpMaxDistance {
+					break
+				}
+				if length := matchLength(i, int(candidate)); length > bestLength {
+					bestLength, bestDistance = length, i-int(candidate)
+				}
+				candidate = chain[candidate]
+			}
+		}
+
+		if bestLength < 3 {
+			tokens = append(tokens, webpToken{argb: pix[i]})
+			insert(i)
+			i++
+			continue
+		}
+		tokens = append(tokens, webpToken{length: bestLength, distance: bestDistance})
+		for end := i + bestLength; i < end; i++ {
+			insert(i)
+		}
+	}
+	return tokens
+}
+
+// webpDistanceCode maps a distance to its code, the first 120 codes are short two dimensional offsets
+// of which only the pixel above and the one to the left are used here.
+func webpDistanceCode(distance, w int) int {
+	switch distance {
+	case w:
+		return 1
+	case 1:
+		return 2
+	}
+	return distance + 120
+}
+
+// webpPrefix splits a length or a distance code into its prefix symbol and extra bits.
+func webpPrefix(value int) (int, uint, uint32) {
+	value--
+	if value < 4 {
+		return value, 0, 0
+	}
+	highest := 0
+	for v := value; v > 1; v >>= 1 {
+		highest++
+	}
+	second := (value >> (highest - 1)) & 1
+	extraBits := uint(highest - 1)
+	return 2*highest + second, extraBits, uint32(value) & (1<<extraBits - 1)
+}
+
+// webpPrefixCode holds the bit reversed canonical Huffman codes of an alphabet.
+type webpPrefixCode struct {
+	codes   []uint32
+	lengths []int
+}
+
+func (c webpPrefixCode) write(bw *webpBitWriter, symbol int) {
+	bw.write(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+// webpWritePrefixCode writes the prefix code of histogram, as a simple code when it has no more than
+// two symbols below 256.
+func webpWritePrefixCode(bw *webpBitWriter, histogram []int) webpPrefixCode {
+	var used []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	if len(used) == 0 {
+		used = []int{0}
+	}
+	if len(used) <= 2 && used[len(used)-1] < 256 {
+		bw.write(1, 1)
+		bw.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(used[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(used[0]), 8)
+		}
+		lengths := make([]int, len(histogram))
+		codes := make([]uint32, len(histogram))
+		if len(used) == 2 {
+			bw.write(uint32(used[1]), 8)
+			lengths[used[0]], lengths[used[1]] = 1, 1
+			codes[used[1]] = 1
+		}
+		return webpPrefixCode{codes: codes, lengths: lengths}
+	}
+
+	lengths := webpCodeLengths(histogram, 15)
+	bw.write(0, 1)
+
+	type lengthToken struct {
+		symbol int
+		extra  uint32
+	}
+	var tokens []lengthToken
+	previous := 8
+	for i := 0; i < len(lengths); {
+		value, run := lengths[i], 1
+		for i+run < len(lengths) && lengths[i+run] == value {
+			run++
+		}
+		i += run
+		if value == 0 {
+			for ; run >= 11; run -= min(run, 138) {
+				tokens = append(tokens, lengthToken{18, uint32(min(run, 138) - 11)})
+			}
+			if run >= 3 {
+				tokens = append(tokens, lengthToken{17, uint32(run - 3)})
+				run = 0
+			}
+		} else {
+			if value != previous {
+				tokens = append(tokens, lengthToken{symbol: value})
+				previous = value
+				run--
+			}
+			for ; run >= 3; run -= min(run, 6) {
+				tokens = append(tokens, lengthToken{16, uint32(min(run, 6) - 3)})
+			}
+		}
+		for ; run > 0; run-- {
+			tokens = append(tokens, lengthToken{symbol: value})
+		}
+	}
+
+	lengthHistogram := make([]int, 19)
+	for _, token := range tokens {
+		lengthHistogram[token.symbol]++
+	}
+	lengthLengths := webpCodeLengths(lengthHistogram, 7)
+	count := 19
+	for count > 4 && lengthLengths[webpCodeLengthOrder[count-1]] == 0 {
+		count--
+	}
+	bw.write(uint32(count-4), 4)
+	for _, symbol := range webpCodeLengthOrder[:count] {
+		bw.write(uint32(lengthLengths[symbol]), 3)
+	}
+	bw.write(0, 1)
+
+	lengthCode := webpPrefixCode{codes: webpCanonicalCodes(lengthLengths), lengths: lengthLengths}
+	for _, token := range tokens {
+		lengthCode.write(bw, token.symbol)
+		switch token.symbol {
+		case 16:
+			bw.write(token.extra, 2)
+		case 17:
+			bw.write(token.extra, 3)
+		case 18:
+			bw.write(token.extra, 7)
+		}
+	}
+	return webpPrefixCode{codes: webpCanonicalCodes(lengths), lengths: lengths}
+}
+
+// webpCodeLengths builds Huffman code lengths of at most limit bits. Rare symbols are counted as more
+// frequent until the tree is shallow enough. A single symbol gets a second one so both decoders
+// agree on a one bit code.
+func webpCodeLengths(histogram []int, limit int) []int {
+	lengths := make([]int, len(histogram))
+	var used []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+	if len(used) < 2 {
+		for symbol := 0; len(used) < 2; symbol++ {
+			if len(used) == 0 || used[0] != symbol {
+				used = append(used, symbol)
+			}
+		}
+		for _, symbol := range used {
+			lengths[symbol] = 1
+		}
+		return lengths
+	}
+
+	for minCount := 1; ; minCount *= 2 {
+		type node struct {
+			weight int
+			parent int
+		}
+		nodes := make([]node, 0, 2*len(used))
+		for _, symbol := range used {
+			nodes = append(nodes, node{weight: max(histogram[symbol], minCount), parent: -1})
+		}
+		leaves := make([]int, len(used))
+		for i := range leaves {
+			leaves[i] = i
+		}
+		sort.SliceStable(leaves, func(a, b int) bool { return nodes[leaves[a]].weight < nodes[leaves[b]].weight })
+
+		// Two queues: the sorted leaves and the merged nodes, which are created in weight order.
+		var merged []int
+		next := func() int {
+			if len(merged) == 0 || (len(leaves) > 0 && nodes[leaves[0]].weight <= nodes[merged[0]].weight) {
+				n := leaves[0]
+				leaves = leaves[1:]
+				return n
+			}
+			n := merged[0]
+			merged = merged[1:]
+			return n
+		}
+		for len(leaves)+len(merged) > 1 {
+			a, b := next(), next()
+			nodes = append(nodes, node{weight: nodes[a].weight + nodes[b].weight, parent: -1})
+			nodes[a].parent, nodes[b].parent = len(nodes)-1, len(nodes)-1
+			merged = append(merged, len(nodes)-1)
+		}
+
+		deepest := 0
+		for i, symbol := range used {
+			depth := 0
+			for n := i; nodes[n].parent >= 0; n = nodes[n].parent {
+				depth++
+			}
+			lengths[symbol] = depth
+			deepest = max(deepest, depth)
+		}
+		if deepest <= limit {
+			return lengths
+		}
+	}
+}
+
+// webpCanonicalCodes assigns the canonical codes of lengths, bit reversed as the decoder reads them
+// least significant bit first.
+func webpCanonicalCodes(lengths []int) []uint32 {
+	var counts [16]uint32
+	for _, length := range lengths {
+		if length > 0 {
+			counts[length]++
+		}
+	}
+	var next [16]uint32
+	code := uint32(0)
+	for length := 1; length < 16; length++ {
+		code = (code + counts[length-1]) << 1
+		next[length] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		code := next[length]
+		next[length]++
+		var reversed uint32
+		for i := 0; i < length; i++ {
+			reversed = reversed<<1 | (code>>i)&1
+		}
+		codes[symbol] = reversed
+	}
+	return codes
+}
+
+// webpBitWriter packs bits least significant first, as VP8L is read.
+type webpBitWriter struct {
+	buf   []byte
+	bits  uint64
+	nBits uint
+}
+
+func (b *webpBitWriter) write(value uint32, n uint) {
+	b.bits |= uint64(value) << b.nBits
+	b.nBits += n
+	for b.nBits >= 8 {
+		b.buf = append(b.buf, byte(b.bits))
+		b.bits >>= 8
+		b.nBits -= 8
+	}
+}
+
+func (b *webpBitWriter) bytes() []byte {
+	if b.nBits > 0 {
+		b.buf = append(b.buf, byte(b.bits))
+		b.bits, b.nBits = 0, 0
+	}
+	return b.buf
+}
@@ -0,0 +1,121 @@
+package helper
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebPRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name  string
+		img   *image.RGBA
+		alpha bool
+	}{
+		{"single pixel", fillImage(1, 1, func(x, y int) color.Color { return color.RGBA{10, 20, 30, 255} }), false},
+		{"solid", fillImage(37, 23, func(x, y int) color.Color { return color.RGBA{200, 100, 50, 255} }), false},
+		{"one column", fillImage(1, 33, func(x, y int) color.Color { return color.RGBA{uint8(y * 7), 0, uint8(y), 255} }), false},
+		{"one row", fillImage(33, 1, func(x, y int) color.Color { return color.RGBA{uint8(x), uint8(random.Intn(3)), 0, 255} }), false},
+		{"odd size across tiles", fillImage(17, 19, func(x, y int) color.Color {
+			return color.RGBA{uint8(x * 15), uint8(y * 13), uint8(x ^ y), 255}
+		}), false},
+		{"noise", fillImage(61, 47, func(x, y int) color.Color {
+			return color.RGBA{uint8(random.Intn(256)), uint8(random.Intn(256)), uint8(random.Intn(256)), 255}
+		}), false},
+		{"repeated pattern", fillImage(129, 65, func(x, y int) color.Color {
+			if (x/3+y/5)%2 == 0 {
+				return color.White
+			}
+			return color.Black
+		}), false},
+		{"translucent", fillImage(45, 31, func(x, y int) color.Color {
+			return color.NRGBA{uint8(y * 8), uint8(x * 5), 77, uint8((x * 17) % 256)}
+		}), true},
+		{"translucent noise", fillImage(23, 29, func(x, y int) color.Color {
+			return color.NRGBA{uint8(random.Intn(256)), uint8(random.Intn(256)), uint8(random.Intn(256)), uint8(random.Intn(256))}
+		}), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.img.Opaque() == test.alpha {
+				t.Fatalf("Opaque() = %v, the test image is not what the case says", test.img.Opaque())
+			}
+			assertWebPRoundTrip(t, test.img)
+		})
+	}
+}
+
+func TestEncodeWebPSubImage(t *testing.T) {
+	img := fillImage(100, 100, func(x, y int) color.Color { return color.RGBA{uint8(x), uint8(y), uint8(x ^ y), 255} })
+	assertWebPRoundTrip(t, img.SubImage(image.Rect(10, 20, 71, 93)).(*image.RGBA))
+}
+
+func TestEncodeWebPIsSniffed(t *testing.T) {
+	body, err := EncodeWebP(fillImage(41, 7, func(x, y int) color.Color { return color.RGBA{1, 2, 3, 255} }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(body)%2 != 0 {
+		t.Fatalf("length %d, RIFF chunks are padded to an even size", len(body))
+	}
+
+	contentType, width, height, err := SniffImage(body)
+	if err != nil || contentType != "image/webp" || width != 41 || height != 7 {
+		t.Fatalf("SniffImage = %s %dx%d %v, want image/webp 41x7", contentType, width, height, err)
+	}
+	if stripped, err := StripImageMetadata(contentType, body); err != nil || !bytes.Equal(stripped, body) {
+		t.Fatalf("StripImageMetadata changed a file without metadata: %v", err)
+	}
+}
+
+func TestEncodeWebPRejectsEmptyImage(t *testing.T) {
+	if _, err := EncodeWebP(image.NewRGBA(image.Rect(0, 0, 0, 5))); err == nil {
+		t.Fatal("EncodeWebP accepted an empty image")
+	}
+}
+
+func assertWebPRoundTrip(t *testing.T, img *image.RGBA) {
+	t.Helper()
+	body, err := EncodeWebP(img)
+	if err != nil {
+		t.Fatalf("EncodeWebP: %v", err)
+	}
+	decoded, err := webp.Decode(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("webp.Decode: %v", err)
+	}
+
+	bounds, decodedBounds := img.Bounds(), decoded.Bounds()
+	if decodedBounds.Dx() != bounds.Dx() || decodedBounds.Dy() != bounds.Dy() {
+		t.Fatalf("decoded %v, want %dx%d", decodedBounds, bounds.Dx(), bounds.Dy())
+	}
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			want := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			got := color.NRGBAModel.Convert(decoded.At(decodedBounds.Min.X+x, decodedBounds.Min.Y+y)).(color.NRGBA)
+			// A transparent pixel has no color to keep.
+			if want.A == 0 && got.A == 0 {
+				continue
+			}
+			if got != want {
+				t.Fatalf("pixel %d,%d = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func fillImage(width, height int, at func(x, y int) color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, at(x, y))
+		}
+	}
+	return img
+}
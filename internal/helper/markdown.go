@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	stdhtml "html"
+	"strings"
+)
+
+// Raw HTML in the source is dropped by goldmark, the sanitizer policies are a second line of defence.
+var (
+	postMarkdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(html.WithHardWraps()),
+	)
+	commentMarkdown = goldmark.New(
+		goldmark.WithExtensions(extension.Strikethrough, extension.Linkify),
+		goldmark.WithRendererOptions(html.WithHardWraps()),
+	)
+
+	postPolicy    = bluemonday.UGCPolicy()
+	commentPolicy = newCommentPolicy()
+	textPolicy    = bluemonday.StrictPolicy()
+)
+
+// RenderMarkdown renders post content to sanitized HTML.
+func RenderMarkdown(source string) string {
+	return render(postMarkdown, postPolicy, source)
+}
+
+// MarkdownToText renders post content to plain text on a single line, for excerpts.
+func MarkdownToText(source string) string {
+	text := stdhtml.UnescapeString(textPolicy.Sanitize(RenderMarkdown(source)))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// RenderCommentMarkdown renders comment content with the restricted comment subset:
+// no headings, images or tables, and every link is nofollow.
+func RenderCommentMarkdown(source string) string {
+	return render(commentMarkdown, commentPolicy, source)
+}
+
+func render(markdown goldmark.Markdown, policy *bluemonday.Policy, source string) string {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return policy.Sanitize(source)
+	}
+	return policy.Sanitize(buf.String())
+}
+
+func newCommentPolicy() *bluemonday.Policy {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("p", "br", "em", "strong", "del", "code", "pre", "blockquote", "ul", "ol", "li")
+	policy.AllowAttrs("href").OnElements("a")
+	policy.AllowStandardURLs()
+	policy.RequireNoFollowOnLinks(true)
+	policy.RequireNoReferrerOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
@@ -0,0 +1,165 @@
+package helper
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// breachedPasswords is a small bundled list of SHA-1 hashes (HASH:COUNT per line) of the most common
+// leaked passwords, always checked even when no BreachedDir is configured.
+//
+//go:embed breached/passwords.txt
+var breachedPasswords string
+
+type PasswordPolicy struct {
+	MinLength  int
+	MinEntropy float64
+	// BreachedDir holds k-anonymity range files named after the first 5 hex characters of the
+	// SHA-1 hash, each line being the remaining 35 characters and a count (SUFFIX:COUNT).
+	BreachedDir string
+	bundled     map[string]map[string]bool
+}
+
+func NewPasswordPolicy(minLength int, minEntropy float64, breachedDir string) *PasswordPolicy {
+	policy := &PasswordPolicy{
+		MinLength:   minLength,
+		MinEntropy:  minEntropy,
+		BreachedDir: breachedDir,
+		bundled:     make(map[string]map[string]bool),
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(breachedPasswords))
+	for scanner.Scan() {
+		hash, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if len(hash) != 40 {
+			continue
+		}
+		prefix, suffix := hash[:5], hash[5:]
+		if policy.bundled[prefix] == nil {
+			policy.bundled[prefix] = make(map[string]bool)
+		}
+		policy.bundled[prefix][suffix] = true
+	}
+
+	return policy
+}
+
+// Check returns every rule the password breaks, an empty result means the password is accepted.
+// personal holds the usernames and emails the password must not contain.
+func (p *PasswordPolicy) Check(password string, personal ...string) []string {
+	var messages []string
+
+	if length := len([]rune(password)); length < p.MinLength {
+		messages = append(messages, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+
+	if entropy := PasswordEntropy(password); entropy < p.MinEntropy {
+		messages = append(messages, "is too easy to guess, use a longer password or mix letters, numbers and symbols")
+	}
+
+	lowerPassword := strings.ToLower(password)
+	for _, token := range personalTokens(personal) {
+		if strings.Contains(lowerPassword, token) {
+			messages = append(messages, "must not contain your username or email")
+			break
+		}
+	}
+
+	if p.IsBreached(password) {
+		messages = append(messages, "has appeared in a known data breach, choose a different password")
+	}
+
+	return messages
+}
+
+func (p *PasswordPolicy) IsBreached(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	if p.bundled[prefix][suffix] {
+		return true
+	}
+
+	if p.BreachedDir == "" {
+		return false
+	}
+
+	file, err := os.Open(filepath.Join(p.BreachedDir, prefix))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		candidate, _, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if strings.EqualFold(candidate, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordEntropy estimates the entropy in bits from the character classes in use,
+// consecutive repeated characters are only counted once.
+func PasswordEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	var length int
+	var previous rune = -1
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r <= unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+		if r != previous {
+			length++
+		}
+		previous = r
+	}
+
+	pool := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+
+	return float64(length) * math.Log2(float64(pool))
+}
+
+func personalTokens(personal []string) []string {
+	var tokens []string
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if local, _, ok := strings.Cut(value, "@"); ok {
+			value = local
+		}
+		if len(value) >= 3 {
+			tokens = append(tokens, value)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,78 @@
+package helper
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)`)
+
+// SpamSignals holds what is known about a comment submission, the durations are zero when unknown.
+type SpamSignals struct {
+	Content          string
+	Honeypot         string
+	Duplicate        bool
+	SinceLastComment time.Duration
+	SinceRender      time.Duration
+}
+
+// SpamFilter scores comments offline from simple heuristics, a higher score is more likely spam.
+type SpamFilter struct {
+	MaxLinks  int
+	Blocklist []string
+}
+
+func NewSpamFilter(maxLinks int, blocklist []string) *SpamFilter {
+	words := make([]string, 0, len(blocklist))
+	for _, word := range blocklist {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+	return &SpamFilter{
+		MaxLinks:  maxLinks,
+		Blocklist: words,
+	}
+}
+
+func (f *SpamFilter) Score(signals *SpamSignals) (int, []string) {
+	score := 0
+	var reasons []string
+
+	if signals.Honeypot != "" {
+		score += 100
+		reasons = append(reasons, "honeypot")
+	}
+
+	if links := len(linkPattern.FindAllString(signals.Content, -1)); links > f.MaxLinks {
+		score += 20 * (links - f.MaxLinks)
+		reasons = append(reasons, "links")
+	}
+
+	content := strings.ToLower(signals.Content)
+	for _, word := range f.Blocklist {
+		if strings.Contains(content, word) {
+			score += 40
+			reasons = append(reasons, "blocklist")
+			break
+		}
+	}
+
+	if signals.Duplicate {
+		score += 50
+		reasons = append(reasons, "duplicate")
+	}
+
+	if signals.SinceLastComment > 0 && signals.SinceLastComment < 10*time.Second {
+		score += 30
+		reasons = append(reasons, "rate")
+	}
+
+	if signals.SinceRender > 0 && signals.SinceRender < 3*time.Second {
+		score += 40
+		reasons = append(reasons, "speed")
+	}
+
+	return score, reasons
+}
@@ -0,0 +1,60 @@
+package helper
+
+import (
+	"fmt"
+	"go-blog/version"
+	"strings"
+	"time"
+)
+
+// wordsPerMinute is the reading speed behind ReadingTime.
+const wordsPerMinute = 200
+
+// TemplateFuncs are the helpers themes can call next to the page data, baseURL is APP_BASE_URL.
+func TemplateFuncs(baseURL string) map[string]any {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return map[string]any{
+		"date":        FormatDate,
+		"isoDate":     func(value any) string { return FormatDate(time.RFC3339, value) },
+		"readingTime": ReadingTime,
+		"postURL":     PostPath,
+		"tagURL":      TagPath,
+		"authorURL":   AuthorPath,
+		"seriesURL":   SeriesPath,
+		"pageURL":     PagePath,
+		"absURL":      func(path string) string { return baseURL + path },
+		"assetURL":    AssetPath,
+	}
+}
+
+// FormatDate formats a time.Time or *time.Time with layout, a nil or zero time gives an empty string.
+// The value comes last so it can be piped: {{.PublishedAt | date "January 2, 2006"}}.
+func FormatDate(layout string, value any) string {
+	var t time.Time
+	switch value := value.(type) {
+	case time.Time:
+		t = value
+	case *time.Time:
+		if value == nil {
+			return ""
+		}
+		t = *value
+	default:
+		return ""
+	}
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// ReadingTime estimates the minutes needed to read content, markdown or HTML, at least one.
+func ReadingTime(content any) int {
+	words := len(strings.Fields(textPolicy.Sanitize(fmt.Sprint(content))))
+	return max(1, (words+wordsPerMinute-1)/wordsPerMinute)
+}
+
+// AssetPath is the URL of a theme asset, the version busts caches after an upgrade.
+func AssetPath(path string) string {
+	return "/assets/" + strings.TrimLeft(path, "/") + "?v=" + version.Version
+}
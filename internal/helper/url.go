@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Paths of the server-rendered pages. Feeds, sitemaps and meta tags link readers to these.
+
+func PostPath(slug string) string {
+	return "/article/" + url.PathEscape(slug)
+}
+
+func TagPath(slug string) string {
+	return "/tag/" + url.PathEscape(slug)
+}
+
+func AuthorPath(username string) string {
+	return "/author/" + url.PathEscape(username)
+}
+
+func SeriesPath(slug string) string {
+	return "/series/" + url.PathEscape(slug)
+}
+
+// PagePath is the path of a page of a paginated list, the first page is the list path itself.
+func PagePath(path string, page int) string {
+	if page <= 1 {
+		return path
+	}
+	return strings.TrimRight(path, "/") + "/page/" + strconv.Itoa(page)
+}
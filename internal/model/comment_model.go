@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+type CommentResponse struct {
+	ID          uint               `json:"id"`
+	ParentID    *uint              `json:"parent_id,omitempty"`
+	Content     string             `json:"content"`
+	ContentHTML string             `json:"content_html"`
+	User        *UserOnPost        `json:"user,omitempty"`
+	Deleted     bool               `json:"deleted,omitempty"`
+	Status      string             `json:"status,omitempty"`
+	SpamScore   int                `json:"spam_score,omitempty"`
+	SpamNotes   string             `json:"spam_notes,omitempty"`
+	Post        *PostResponse      `json:"post,omitempty"`
+	Replies     []*CommentResponse `json:"replies,omitempty"`
+	EditedAt    *time.Time         `json:"edited_at,omitempty"`
+	CreatedAt   *time.Time         `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time         `json:"updated_at,omitempty"`
+}
+
+// CreateCommentRequest carries two anti-spam fields: Website is a honeypot that must stay empty
+// and RenderedAt is the unix time in milliseconds at which the comment form was shown.
+type CreateCommentRequest struct {
+	Slug       string `json:"-" validate:"required"`
+	UserID     string `json:"-" validate:"required,uuid4"`
+	ParentID   *uint  `json:"parent_id,omitempty"`
+	Content    string `json:"content" validate:"required,max=5000"`
+	Website    string `json:"website,omitempty"`
+	RenderedAt int64  `json:"rendered_at,omitempty"`
+}
+
+type UpdateCommentRequest struct {
+	ID      uint   `json:"-" validate:"required"`
+	UserID  string `json:"-" validate:"required,uuid4"`
+	Content string `json:"content" validate:"required,max=5000"`
+}
+
+type SearchCommentRequest struct {
+	Slug     string     `json:"-" validate:"required"`
+	Mode     string     `json:"mode" form:"mode" validate:"omitempty,oneof=tree flat"`
+	Paginate Pagination `json:"paginate"`
+}
+
+type SearchModerationRequest struct {
+	Status      string     `json:"status" validate:"required,oneof=pending approved rejected spam"`
+	PostOwnerID string     `json:"-"`
+	Paginate    Pagination `json:"paginate"`
+}
+
+// ModerateCommentRequest changes the status of a comment, ModeratorID is empty for admins
+// and otherwise has to be the author of the commented post.
+type ModerateCommentRequest struct {
+	ID          uint   `json:"-" validate:"required"`
+	ModeratorID string `json:"-"`
+	Status      string `json:"status" validate:"required,oneof=approved rejected spam"`
+}
@@ -0,0 +1,73 @@
+package converter
+
+import (
+	"go-blog/internal/entity"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+)
+
+// CommentToResponse is the public view, deleted and unapproved comments only keep their place in the thread.
+func CommentToResponse(comment *entity.Comment) *model.CommentResponse {
+	if comment.DeletedAt.Valid || comment.Status != entity.CommentStatusApproved {
+		return &model.CommentResponse{
+			ID:        comment.ID,
+			ParentID:  comment.ParentID,
+			Deleted:   true,
+			CreatedAt: comment.CreatedAt,
+		}
+	}
+
+	return &model.CommentResponse{
+		ID:          comment.ID,
+		ParentID:    comment.ParentID,
+		Content:     comment.Content,
+		ContentHTML: helper.RenderCommentMarkdown(comment.Content),
+		User: &model.UserOnPost{
+			ID:       comment.User.ID,
+			Name:     comment.User.Name,
+			Username: comment.User.Username,
+		},
+		EditedAt:  comment.EditedAt,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+// CommentToAuthorResponse is the view for the commenter, it includes the status but not the spam
+// details, which would tell a spammer which check caught them.
+func CommentToAuthorResponse(comment *entity.Comment) *model.CommentResponse {
+	response := CommentToModerationResponse(comment)
+	response.SpamScore = 0
+	response.SpamNotes = ""
+	return response
+}
+
+// CommentToModerationResponse is the view for moderators, it includes the status and spam details.
+func CommentToModerationResponse(comment *entity.Comment) *model.CommentResponse {
+	response := &model.CommentResponse{
+		ID:          comment.ID,
+		ParentID:    comment.ParentID,
+		Content:     comment.Content,
+		ContentHTML: helper.RenderCommentMarkdown(comment.Content),
+		User: &model.UserOnPost{
+			ID:       comment.User.ID,
+			Name:     comment.User.Name,
+			Username: comment.User.Username,
+		},
+		Deleted:   comment.DeletedAt.Valid,
+		Status:    comment.Status,
+		SpamScore: comment.SpamScore,
+		SpamNotes: comment.SpamNotes,
+		EditedAt:  comment.EditedAt,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+	if comment.Post.ID != 0 {
+		response.Post = &model.PostResponse{
+			ID:    comment.Post.ID,
+			Title: comment.Post.Title,
+			Slug:  comment.Post.Slug,
+		}
+	}
+	return response
+}
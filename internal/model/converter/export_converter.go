@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+)
+
+func DataExportToResponse(export *entity.DataExport, downloadURL string) *model.DataExportResponse {
+	return &model.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		Size:        export.Size,
+		DownloadURL: downloadURL,
+		ExpiresAt:   &export.ExpiresAt,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+	}
+}
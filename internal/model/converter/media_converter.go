@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"strconv"
+	"strings"
+)
+
+func MediaToResponse(media *entity.Media) *model.MediaResponse {
+	response := MediaToCoverResponse(media)
+	response.FileName = media.FileName
+	response.ContentType = media.ContentType
+	response.Size = media.Size
+	response.CreatedAt = media.CreatedAt
+	return response
+}
+
+// MediaToCoverResponse leaves out the upload details, a cover is shown to every reader.
+func MediaToCoverResponse(media *entity.Media) *model.MediaResponse {
+	response := &model.MediaResponse{
+		ID:     media.ID,
+		URL:    media.URL,
+		Width:  media.Width,
+		Height: media.Height,
+	}
+
+	srcSet := make([]string, 0, len(media.Variants)+1)
+	var webpSrcSet []string
+	for _, variant := range media.Variants {
+		response.Variants = append(response.Variants, model.MediaVariantResponse{
+			URL:         variant.URL,
+			ContentType: variant.ContentType,
+			Width:       variant.Width,
+			Height:      variant.Height,
+		})
+		candidate := variant.URL + " " + strconv.Itoa(variant.Width) + "w"
+		if variant.ContentType == "image/webp" {
+			webpSrcSet = append(webpSrcSet, candidate)
+			continue
+		}
+		srcSet = append(srcSet, candidate)
+	}
+	response.SrcSet = strings.Join(append(srcSet, media.URL+" "+strconv.Itoa(media.Width)+"w"), ", ")
+	response.WebPSrcSet = strings.Join(webpSrcSet, ", ")
+	return response
+}
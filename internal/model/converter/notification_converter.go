@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+)
+
+func NotificationToResponse(notification *entity.Notification) *model.NotificationResponse {
+	return &model.NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		Message:   notification.Message,
+		Link:      notification.Link,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func NotificationPreferenceToResponse(preference *entity.NotificationPreference) *model.NotificationPreferenceResponse {
+	return &model.NotificationPreferenceResponse{
+		Type:    preference.Type,
+		Enabled: preference.Enabled,
+		Email:   preference.Email,
+	}
+}
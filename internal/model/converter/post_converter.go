@@ -10,6 +10,18 @@ func PostToResponse(post *entity.Post) *model.PostResponse {
 	for _, tag := range post.Tags {
 		tags = append(tags, TagToPostResponse(tag))
 	}
+	var cover *model.PostImageResponse
+	if post.CoverMedia != nil {
+		cover = PostCoverToResponse(post)
+	}
+	var gallery []model.PostImageResponse
+	for _, image := range post.Gallery {
+		gallery = append(gallery, model.PostImageResponse{
+			MediaResponse: MediaToCoverResponse(&image.Media),
+			Alt:           image.Alt,
+			Caption:       image.Caption,
+		})
+	}
 	return &model.PostResponse{
 		ID:      post.ID,
 		Title:   post.Title,
@@ -21,8 +33,71 @@ func PostToResponse(post *entity.Post) *model.PostResponse {
 			Name:     post.User.Name,
 			Username: post.User.Username,
 		},
-		PublishedAt: post.PublishedAt,
-		CreatedAt:   post.CreatedAt,
-		UpdatedAt:   post.UpdatedAt,
+		Authors:      PostAuthorsToResponse(post),
+		CommentCount: post.CommentCount,
+		Reactions:    ReactionCountsToResponse(post.ReactionCounts),
+		PublishedAt:  post.PublishedAt,
+		CreatedAt:    post.CreatedAt,
+		UpdatedAt:    post.UpdatedAt,
+		Cover:        cover,
+		Gallery:      gallery,
+	}
+}
+
+// PostCoverToResponse falls back to the title for a cover without alt text.
+func PostCoverToResponse(post *entity.Post) *model.PostImageResponse {
+	alt := post.CoverAlt
+	if alt == "" {
+		alt = post.Title
+	}
+	return &model.PostImageResponse{
+		MediaResponse: MediaToCoverResponse(post.CoverMedia),
+		Alt:           alt,
+		Caption:       post.CoverCaption,
+	}
+}
+
+// PostAuthorsToResponse lists the primary author first, followed by the accepted contributors loaded with the post.
+func PostAuthorsToResponse(post *entity.Post) []model.PostAuthorResponse {
+	authors := []model.PostAuthorResponse{{
+		ID:       post.User.ID,
+		Name:     post.User.Name,
+		Username: post.User.Username,
+		Role:     entity.PostAuthorRoleAuthor,
+		Primary:  true,
+	}}
+	for _, author := range post.Authors {
+		// A credit of a deleted user comes without it, and a transfer can make a contributor the primary author.
+		if author.User.ID == "" || author.UserID == post.UserID {
+			continue
+		}
+		authors = append(authors, *PostAuthorToResponse(&author))
+	}
+	return authors
+}
+
+func PostAuthorToResponse(author *entity.PostAuthor) *model.PostAuthorResponse {
+	return &model.PostAuthorResponse{
+		ID:       author.User.ID,
+		Name:     author.User.Name,
+		Username: author.User.Username,
+		Role:     author.Role,
+		Pending:  author.AcceptedAt == nil,
+	}
+}
+
+func PostInvitationToResponse(author *entity.PostAuthor) *model.PostInvitationResponse {
+	return &model.PostInvitationResponse{
+		Post: model.PostOnInvitation{
+			Title: author.Post.Title,
+			Slug:  author.Post.Slug,
+		},
+		Role: author.Role,
+		InvitedBy: model.UserOnPost{
+			ID:       author.Post.User.ID,
+			Name:     author.Post.User.Name,
+			Username: author.Post.User.Username,
+		},
+		CreatedAt: author.CreatedAt,
 	}
 }
@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"go-blog/internal/entity"
+)
+
+// ReactionCountsToResponse lists every reaction type, so clients always get the full set even at zero.
+func ReactionCountsToResponse(counts []entity.PostReactionCount) map[string]int64 {
+	reactions := make(map[string]int64, len(entity.ReactionTypes))
+	for _, reactionType := range entity.ReactionTypes {
+		reactions[reactionType] = 0
+	}
+	for _, count := range counts {
+		if _, ok := reactions[count.Type]; ok {
+			reactions[count.Type] = count.Count
+		}
+	}
+	return reactions
+}
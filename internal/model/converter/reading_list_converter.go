@@ -0,0 +1,18 @@
+package converter
+
+import (
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+)
+
+func ReadingListToResponse(list *entity.ReadingList, postCount int64) *model.ReadingListResponse {
+	return &model.ReadingListResponse{
+		ID:          list.ID,
+		Name:        list.Name,
+		Description: list.Description,
+		Visibility:  list.Visibility,
+		PostCount:   postCount,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
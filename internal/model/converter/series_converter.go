@@ -0,0 +1,48 @@
+package converter
+
+import (
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+)
+
+func SeriesToResponse(series *entity.Series, postCount int64) *model.SeriesResponse {
+	return &model.SeriesResponse{
+		ID:          series.ID,
+		Title:       series.Title,
+		Slug:        series.Slug,
+		Description: series.Description,
+		User: model.UserOnPost{
+			ID:       series.User.ID,
+			Name:     series.User.Name,
+			Username: series.User.Username,
+		},
+		PostCount: postCount,
+		CreatedAt: series.CreatedAt,
+		UpdatedAt: series.UpdatedAt,
+	}
+}
+
+// SeriesToPostResponse places the post postId among parts, the published parts of the series in order.
+// It returns nil when the post is not one of them.
+func SeriesToPostResponse(series *entity.Series, parts []entity.Post, postId uint) *model.PostSeriesResponse {
+	for i, part := range parts {
+		if part.ID != postId {
+			continue
+		}
+		response := &model.PostSeriesResponse{
+			ID:         series.ID,
+			Title:      series.Title,
+			Slug:       series.Slug,
+			Part:       i + 1,
+			TotalParts: len(parts),
+		}
+		if i > 0 {
+			response.Previous = &model.SeriesPartResponse{Title: parts[i-1].Title, Slug: parts[i-1].Slug}
+		}
+		if i < len(parts)-1 {
+			response.Next = &model.SeriesPartResponse{Title: parts[i+1].Title, Slug: parts[i+1].Slug}
+		}
+		return response
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package converter
+
+import (
+	"go-blog/internal/model"
+	"time"
+)
+
+const (
+	RSSPath      = "/feed.xml"
+	AtomPath     = "/atom.xml"
+	JSONFeedPath = "/feed.json"
+)
+
+func SyndicationToRSS(feed *model.SyndicationFeed) *model.RSSFeed {
+	items := make([]model.RSSItem, len(feed.Items))
+	for i, item := range feed.Items {
+		description := item.ContentHTML
+		if description == "" {
+			description = item.Summary
+		}
+		items[i] = model.RSSItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			GUID:        model.RSSGUID{IsPermaLink: true, Value: item.URL},
+			Description: description,
+			Creator:     item.AuthorName,
+			Categories:  item.Tags,
+			PubDate:     item.PublishedAt.UTC().Format(time.RFC1123Z),
+		}
+	}
+
+	var lastBuildDate string
+	if feed.Updated != nil {
+		lastBuildDate = feed.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	return &model.RSSFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: model.RSSChannel{
+			Title:         feed.Title,
+			Link:          feed.HomeURL,
+			Description:   feed.Description,
+			AtomLink:      model.RSSAtomLink{Href: feed.SelfURL + RSSPath, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: lastBuildDate,
+			Items:         items,
+		},
+	}
+}
+
+// SyndicationToAtom dates an empty feed at the epoch, Atom requires an updated element and a
+// fixed value keeps the ETag stable.
+func SyndicationToAtom(feed *model.SyndicationFeed) *model.AtomFeed {
+	entries := make([]model.AtomEntry, len(feed.Items))
+	for i, item := range feed.Items {
+		categories := make([]model.AtomCategory, len(item.Tags))
+		for j, tag := range item.Tags {
+			categories[j] = model.AtomCategory{Term: tag}
+		}
+
+		entry := model.AtomEntry{
+			Title:      item.Title,
+			ID:         item.URL,
+			Links:      []model.AtomLink{{Href: item.URL, Rel: "alternate", Type: "text/html"}},
+			Published:  item.PublishedAt.UTC().Format(time.RFC3339),
+			Updated:    item.UpdatedAt.UTC().Format(time.RFC3339),
+			Author:     model.AtomPerson{Name: item.AuthorName, URI: item.AuthorURL},
+			Categories: categories,
+			Summary:    &model.AtomText{Type: "text", Body: item.Summary},
+		}
+		if item.ContentHTML != "" {
+			entry.Content = &model.AtomText{Type: "html", Body: item.ContentHTML}
+		}
+		entries[i] = entry
+	}
+
+	updated := time.Unix(0, 0)
+	if feed.Updated != nil {
+		updated = *feed.Updated
+	}
+
+	return &model.AtomFeed{
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feed.SelfURL + AtomPath,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links: []model.AtomLink{
+			{Href: feed.SelfURL + AtomPath, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.HomeURL, Rel: "alternate"},
+		},
+		Entries: entries,
+	}
+}
+
+func SyndicationToJSONFeed(feed *model.SyndicationFeed) *model.JSONFeed {
+	items := make([]model.JSONFeedItem, len(feed.Items))
+	for i, item := range feed.Items {
+		items[i] = model.JSONFeedItem{
+			ID:            item.URL,
+			URL:           item.URL,
+			Title:         item.Title,
+			ContentHTML:   item.ContentHTML,
+			Summary:       item.Summary,
+			DatePublished: item.PublishedAt.UTC().Format(time.RFC3339),
+			DateModified:  item.UpdatedAt.UTC().Format(time.RFC3339),
+			Authors:       []model.JSONFeedAuthor{{Name: item.AuthorName, URL: item.AuthorURL}},
+			Tags:          item.Tags,
+		}
+		// JSON Feed needs content_html or content_text on every item.
+		if item.ContentHTML == "" {
+			items[i].ContentText = item.Summary
+		}
+	}
+
+	return &model.JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.HomeURL,
+		FeedURL:     feed.SelfURL + JSONFeedPath,
+		Description: feed.Description,
+		Items:       items,
+	}
+}
@@ -7,12 +7,42 @@ import (
 
 func UserToResponse(user *entity.User) *model.UserResponse {
 	return &model.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: &user.CreatedAt,
-		UpdatedAt: &user.UpdatedAt,
+		ID:          user.ID,
+		Name:        user.Name,
+		Username:    user.Username,
+		Email:       user.Email,
+		Bio:         user.Bio,
+		AvatarURL:   user.AvatarURL,
+		Website:     user.Website,
+		SocialLinks: UserToSocialLinks(user),
+		CreatedAt:   &user.CreatedAt,
+		UpdatedAt:   &user.UpdatedAt,
+	}
+}
+
+func UserToProfileResponse(user *entity.User, postCount int64, latestPosts []model.PostResponse) *model.ProfileResponse {
+	return &model.ProfileResponse{
+		Name:        user.Name,
+		Username:    user.Username,
+		Bio:         user.Bio,
+		AvatarURL:   user.AvatarURL,
+		Website:     user.Website,
+		SocialLinks: UserToSocialLinks(user),
+		PostCount:   postCount,
+		LatestPosts: latestPosts,
+		CreatedAt:   &user.CreatedAt,
+	}
+}
+
+func UserToSocialLinks(user *entity.User) *model.SocialLinks {
+	if user.Twitter == "" && user.Github == "" && user.Linkedin == "" && user.Mastodon == "" {
+		return nil
+	}
+	return &model.SocialLinks{
+		Twitter:  user.Twitter,
+		Github:   user.Github,
+		Linkedin: user.Linkedin,
+		Mastodon: user.Mastodon,
 	}
 }
 
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+type DataExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Size        int64      `json:"size,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type DownloadDataExportRequest struct {
+	ID        string `json:"-" validate:"required,uuid4"`
+	Expires   int64  `json:"-" validate:"required"`
+	Signature string `json:"-" validate:"required,hexadecimal"`
+}
+
+// AccountExport is the account.json file of a data export.
+type AccountExport struct {
+	ID                string                   `json:"id"`
+	Email             string                   `json:"email"`
+	Username          string                   `json:"username"`
+	PendingEmail      string                   `json:"pending_email,omitempty"`
+	PreviousUsernames []PreviousUsernameExport `json:"previous_usernames"`
+	PostCount         int                      `json:"post_count"`
+	CreatedAt         time.Time                `json:"created_at"`
+	UpdatedAt         time.Time                `json:"updated_at"`
+	ExportedAt        time.Time                `json:"exported_at"`
+}
+
+type PreviousUsernameExport struct {
+	Username  string     `json:"username"`
+	ChangedAt *time.Time `json:"changed_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+type FollowResponse struct {
+	Following bool `json:"following"`
+}
+
+type FollowUserRequest struct {
+	UserId   string `json:"-" validate:"required"`
+	Username string `json:"-" validate:"required"`
+}
+
+type FollowTagRequest struct {
+	UserId string `json:"-" validate:"required"`
+	Slug   string `json:"-" validate:"required"`
+}
+
+type FollowingResponse struct {
+	Users []UserOnPost   `json:"users"`
+	Tags  []*TagResponse `json:"tags"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"io"
+	"time"
+)
+
+type MediaResponse struct {
+	ID          string                 `json:"id"`
+	URL         string                 `json:"url"`
+	FileName    string                 `json:"file_name,omitempty"`
+	ContentType string                 `json:"content_type,omitempty"`
+	Size        int64                  `json:"size,omitempty"`
+	Width       int                    `json:"width"`
+	Height      int                    `json:"height"`
+	Variants    []MediaVariantResponse `json:"variants,omitempty"`
+	// SrcSet lists the variants and the original by width, ready for an img srcset attribute.
+	SrcSet string `json:"srcset"`
+	// WebPSrcSet lists the WebP variants by width, for a picture source of type image/webp.
+	WebPSrcSet string     `json:"webp_srcset,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+// PostImageResponse is an image of a post, the cover or one of its gallery.
+type PostImageResponse struct {
+	*MediaResponse
+	Alt     string `json:"alt"`
+	Caption string `json:"caption,omitempty"`
+}
+
+type MediaVariantResponse struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+// UploadMediaRequest is a file of a multipart upload, Size is the one announced by the client.
+type UploadMediaRequest struct {
+	UserId   string    `json:"-" validate:"required"`
+	FileName string    `json:"file_name"`
+	Size     int64     `json:"size" validate:"min=1"`
+	File     io.Reader `json:"-" validate:"required"`
+}
+
+type SearchMediaRequest struct {
+	UserId   string     `json:"-" validate:"required"`
+	Paginate Pagination `json:"paginate"`
+}
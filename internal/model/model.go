@@ -1,9 +1,12 @@
 package model
 
+import "strings"
+
 type WebResponse[T any] struct {
-	Data   T             `json:"data"`
-	Paging *PageMetadata `json:"paging,omitempty"`
-	Errors string        `json:"errors,omitempty"`
+	Data   T               `json:"data"`
+	Paging *PageMetadata   `json:"paging,omitempty"`
+	Cursor *CursorMetadata `json:"cursor,omitempty"`
+	Errors string          `json:"errors,omitempty"`
 }
 
 type PageResponse[T any] struct {
@@ -18,7 +21,31 @@ type PageMetadata struct {
 	TotalPage int64 `json:"total_page"`
 }
 
+// CursorMetadata pages through lists that keep growing at the top, NextCursor is empty on the last page.
+type CursorMetadata struct {
+	Size       int    `json:"size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
 type Pagination struct {
 	Page int `json:"page" form:"page" validate:"min=1"`
 	Size int `json:"size" form:"size" validate:"min=1,max=100"`
 }
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is rendered by the error handler as a 400 with the per-field messages.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + " " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+type NotificationResponse struct {
+	ID        uint       `json:"id"`
+	Type      string     `json:"type"`
+	Message   string     `json:"message"`
+	Link      string     `json:"link,omitempty"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type NotificationListResponse struct {
+	UnreadCount   int64                   `json:"unread_count"`
+	Notifications []*NotificationResponse `json:"notifications"`
+}
+
+type SearchNotificationRequest struct {
+	UserId   string     `json:"-" validate:"required"`
+	Unread   bool       `json:"unread" form:"unread"`
+	Paginate Pagination `json:"paginate"`
+}
+
+type MarkNotificationsReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	Email   bool   `json:"email"`
+}
+
+// NotificationPreferenceRequest changes one type, the fields that are left out keep their value.
+type NotificationPreferenceRequest struct {
+	Type    string `json:"type" validate:"required,oneof=post_published posts_transferred coauthor_invited post_edited account_updated account_deleted account_restored"`
+	Enabled *bool  `json:"enabled"`
+	Email   *bool  `json:"email"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	UserId      string                          `json:"-" validate:"required"`
+	Preferences []NotificationPreferenceRequest `json:"preferences" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// PostAuthorResponse is a contributor of a post, the primary author comes first with the author role.
+type PostAuthorResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Primary  bool   `json:"primary,omitempty"`
+	Pending  bool   `json:"pending,omitempty"`
+}
+
+// PostInvitationResponse is an invitation to be credited on a post that was not answered yet.
+type PostInvitationResponse struct {
+	Post      PostOnInvitation `json:"post"`
+	Role      string           `json:"role"`
+	InvitedBy UserOnPost       `json:"invited_by"`
+	CreatedAt *time.Time       `json:"created_at,omitempty"`
+}
+
+type PostOnInvitation struct {
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type InvitePostAuthorRequest struct {
+	Slug     string `json:"-" validate:"required"`
+	UserId   string `json:"-" validate:"required"`
+	Username string `json:"username" validate:"required"`
+	Role     string `json:"role" validate:"required,oneof=author editor reviewer"`
+}
+
+// PostAuthorRequest names the contributor Username of a post, UserId is the logged in user acting on it.
+type PostAuthorRequest struct {
+	Slug     string `json:"-" validate:"required"`
+	UserId   string `json:"-" validate:"required"`
+	Username string `json:"-"`
+}
@@ -3,21 +3,96 @@ package model
 import "time"
 
 type PostResponse struct {
-	ID          uint           `json:"id,omitempty"`
-	Title       string         `json:"name,omitempty"`
-	Slug        string         `json:"slug,omitempty"`
-	Content     string         `json:"content,omitempty"`
-	Tags        []*TagResponse `json:"tags,omitempty"`
-	User        UserOnPost     `json:"user,omitempty"`
-	PublishedAt *time.Time     `json:"published_at,omitempty"`
-	CreatedAt   *time.Time     `json:"created_at,omitempty"`
-	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+	ID             uint                 `json:"id,omitempty"`
+	Title          string               `json:"name,omitempty"`
+	Slug           string               `json:"slug,omitempty"`
+	Content        string               `json:"content,omitempty"`
+	Tags           []*TagResponse       `json:"tags,omitempty"`
+	User           UserOnPost           `json:"user,omitempty"`
+	Authors        []PostAuthorResponse `json:"authors,omitempty"`
+	CommentCount   int64                `json:"comment_count"`
+	Reactions      map[string]int64     `json:"reactions"`
+	ReactedByMe    []string             `json:"reacted_by_me,omitempty"`
+	BookmarkedByMe bool                 `json:"bookmarked_by_me,omitempty"`
+	PublishedAt    *time.Time           `json:"published_at,omitempty"`
+	CreatedAt      *time.Time           `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time           `json:"updated_at,omitempty"`
+	Cover          *PostImageResponse   `json:"cover,omitempty"`
+	Gallery        []PostImageResponse  `json:"gallery,omitempty"`
+	Series         *PostSeriesResponse  `json:"series,omitempty"`
+	Meta           *PostMeta            `json:"meta,omitempty"`
+}
+
+// PostMeta holds the tags a page head needs, the SEO fields of the post with their fallbacks applied.
+type PostMeta struct {
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	CanonicalURL string          `json:"canonical_url"`
+	Image        string          `json:"image,omitempty"`
+	Robots       string          `json:"robots"`
+	OpenGraph    OpenGraphMeta   `json:"og"`
+	Twitter      TwitterCardMeta `json:"twitter"`
+}
+
+type OpenGraphMeta struct {
+	Type          string     `json:"type"`
+	SiteName      string     `json:"site_name"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	URL           string     `json:"url"`
+	Image         string     `json:"image,omitempty"`
+	Author        string     `json:"article:author,omitempty"`
+	Tags          []string   `json:"article:tag,omitempty"`
+	PublishedTime *time.Time `json:"article:published_time,omitempty"`
+	ModifiedTime  *time.Time `json:"article:modified_time,omitempty"`
+}
+
+type TwitterCardMeta struct {
+	Card        string `json:"card"`
+	Site        string `json:"site,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Image       string `json:"image,omitempty"`
 }
 
 type CreatePostRequest struct {
 	Title   string              `json:"title" validate:"required,max=100"`
 	Content string              `json:"content" validate:"required"`
 	Tags    []CreateTagResponse `json:"tags"`
+
+	MetaTitle       string `json:"meta_title" validate:"max=100"`
+	MetaDescription string `json:"meta_description" validate:"max=300"`
+	CanonicalURL    string `json:"canonical_url" validate:"omitempty,url,max=255"`
+	SocialImage     string `json:"social_image" validate:"omitempty,url,max=255"`
+	NoIndex         bool   `json:"noindex"`
+
+	// CoverMediaID and the gallery images are uploads of the author, the cover alt defaults to the title.
+	CoverMediaID string             `json:"cover_media_id" validate:"omitempty,uuid4"`
+	CoverAlt     string             `json:"cover_alt" validate:"max=300"`
+	CoverCaption string             `json:"cover_caption" validate:"max=500"`
+	Gallery      []PostImageRequest `json:"gallery" validate:"max=50,dive"`
+}
+
+// UpdatePostRequest leaves the fields that are sent empty unchanged, Tags replace the tags when sent.
+// The slug, cover and gallery stay as they are.
+type UpdatePostRequest struct {
+	Slug    string              `json:"-" validate:"required"`
+	UserId  string              `json:"-" validate:"required"`
+	Title   string              `json:"title,omitempty" validate:"max=100"`
+	Content string              `json:"content,omitempty"`
+	Tags    []CreateTagResponse `json:"tags,omitempty"`
+
+	MetaTitle       string `json:"meta_title,omitempty" validate:"max=100"`
+	MetaDescription string `json:"meta_description,omitempty" validate:"max=300"`
+	CanonicalURL    string `json:"canonical_url,omitempty" validate:"omitempty,url,max=255"`
+	SocialImage     string `json:"social_image,omitempty" validate:"omitempty,url,max=255"`
+	NoIndex         *bool  `json:"noindex,omitempty"`
+}
+
+type PostImageRequest struct {
+	MediaID string `json:"media_id" validate:"required,uuid4"`
+	Alt     string `json:"alt" validate:"required,max=300"`
+	Caption string `json:"caption" validate:"max=500"`
 }
 
 type UserOnPost struct {
@@ -27,10 +102,30 @@ type UserOnPost struct {
 }
 
 type SearchPostRequest struct {
-	Username string     `json:"username" form:"username" validate:"min=1,max=30"`
-	Sort     string     `json:"sort" form:"sort" validate:"min=1"`
-	Title    string     `json:"title" form:"title" validate:"max=100"`
-	Tags     []string   `json:"tags" form:"tags"`
-	UserId   string     `json:"-"`
-	Paginate Pagination `json:"paginate"`
+	Username   string     `json:"username" form:"username" validate:"min=1,max=30"`
+	Sort       string     `json:"sort" form:"sort" validate:"min=1"`
+	Title      string     `json:"title" form:"title" validate:"max=100"`
+	Tags       []string   `json:"tags" form:"tags"`
+	UserId     string     `json:"-"`
+	ViewerId   string     `json:"-"`
+	SeriesId   uint       `json:"-"`
+	Bookmarked bool       `json:"bookmarked" form:"bookmarked"`
+	Published  bool       `json:"-"`
+	Paginate   Pagination `json:"paginate"`
+}
+
+type FeedRequest struct {
+	UserId            string     `json:"-" validate:"required"`
+	Cursor            string     `json:"cursor" form:"cursor"`
+	Size              int        `json:"size" form:"size" validate:"min=1,max=100"`
+	BeforePublishedAt *time.Time `json:"-"`
+	BeforeID          uint       `json:"-"`
+}
+
+// PostUpdatedEvent is streamed to the subscribers of a post, Change is comments, reactions or content.
+type PostUpdatedEvent struct {
+	Slug         string           `json:"slug"`
+	Change       string           `json:"change"`
+	CommentCount int64            `json:"comment_count"`
+	Reactions    map[string]int64 `json:"reactions"`
 }
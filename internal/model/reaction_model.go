@@ -0,0 +1,12 @@
+package model
+
+type ReactionRequest struct {
+	Slug   string `json:"-" validate:"required"`
+	UserId string `json:"-" validate:"required"`
+	Type   string `json:"type" validate:"required,oneof=like love clap insightful laugh"`
+}
+
+type ReactionSummaryResponse struct {
+	Reactions   map[string]int64 `json:"reactions"`
+	ReactedByMe []string         `json:"reacted_by_me"`
+}
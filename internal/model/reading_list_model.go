@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+type BookmarkResponse struct {
+	Slug       string `json:"slug"`
+	Bookmarked bool   `json:"bookmarked"`
+}
+
+type BookmarkRequest struct {
+	Slug   string `json:"-" validate:"required"`
+	UserId string `json:"-" validate:"required"`
+}
+
+type ReadingListResponse struct {
+	ID          uint           `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Visibility  string         `json:"visibility"`
+	PostCount   int64          `json:"post_count"`
+	Posts       []PostResponse `json:"posts,omitempty"`
+	CreatedAt   *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+}
+
+type CreateReadingListRequest struct {
+	UserId      string `json:"-" validate:"required"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=255"`
+	Visibility  string `json:"visibility" validate:"omitempty,oneof=private public"`
+}
+
+// UpdateReadingListRequest leaves the fields that are sent empty unchanged.
+type UpdateReadingListRequest struct {
+	ID          uint   `json:"-" validate:"required"`
+	UserId      string `json:"-" validate:"required"`
+	Name        string `json:"name,omitempty" validate:"max=100"`
+	Description string `json:"description,omitempty" validate:"max=255"`
+	Visibility  string `json:"visibility,omitempty" validate:"omitempty,oneof=private public"`
+}
+
+type GetReadingListRequest struct {
+	ID       uint   `json:"-" validate:"required"`
+	ViewerId string `json:"-"`
+}
+
+// ReadingListPostRequest adds or removes a post, Position is 1 based and appends the post when empty.
+type ReadingListPostRequest struct {
+	ListID   uint   `json:"-" validate:"required"`
+	UserId   string `json:"-" validate:"required"`
+	Slug     string `json:"slug" validate:"required"`
+	Position int    `json:"position,omitempty" validate:"min=0"`
+}
+
+// ReorderReadingListRequest gives the new order of the list, it has to name every post of the list once.
+type ReorderReadingListRequest struct {
+	ListID uint     `json:"-" validate:"required"`
+	UserId string   `json:"-" validate:"required"`
+	Slugs  []string `json:"slugs" validate:"required,min=1,dive,required"`
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+type SeriesResponse struct {
+	ID          uint           `json:"id"`
+	Title       string         `json:"title"`
+	Slug        string         `json:"slug"`
+	Description string         `json:"description,omitempty"`
+	User        UserOnPost     `json:"user"`
+	PostCount   int64          `json:"post_count"`
+	Posts       []PostResponse `json:"posts,omitempty"`
+	CreatedAt   *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+}
+
+// PostSeriesResponse places a post in its series, Part is 1 based among the published parts.
+type PostSeriesResponse struct {
+	ID         uint                `json:"id"`
+	Title      string              `json:"title"`
+	Slug       string              `json:"slug"`
+	Part       int                 `json:"part"`
+	TotalParts int                 `json:"total_parts"`
+	Previous   *SeriesPartResponse `json:"previous,omitempty"`
+	Next       *SeriesPartResponse `json:"next,omitempty"`
+}
+
+type SeriesPartResponse struct {
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type CreateSeriesRequest struct {
+	UserId      string `json:"-" validate:"required"`
+	Title       string `json:"title" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// UpdateSeriesRequest leaves the fields that are sent empty unchanged, the slug stays the same.
+type UpdateSeriesRequest struct {
+	ID          uint   `json:"-" validate:"required"`
+	UserId      string `json:"-" validate:"required"`
+	Title       string `json:"title,omitempty" validate:"max=100"`
+	Description string `json:"description,omitempty" validate:"max=500"`
+}
+
+// SeriesPostRequest adds or removes a post, Position is 1 based and appends the post when empty.
+type SeriesPostRequest struct {
+	SeriesID uint   `json:"-" validate:"required"`
+	UserId   string `json:"-" validate:"required"`
+	Slug     string `json:"slug" validate:"required"`
+	Position int    `json:"position,omitempty" validate:"min=0"`
+}
+
+// ReorderSeriesRequest gives the new order of the series, it has to name every part once.
+type ReorderSeriesRequest struct {
+	SeriesID uint     `json:"-" validate:"required"`
+	UserId   string   `json:"-" validate:"required"`
+	Slugs    []string `json:"slugs" validate:"required,min=1,dive,required"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"html/template"
+	"time"
+)
+
+// SitePage is embedded in every server-rendered page, the layout fills the head from it.
+type SitePage struct {
+	SiteName     string
+	Title        string
+	Description  string
+	CanonicalURL string
+	Robots       string
+	FeedURL      string
+	Meta         *PostMeta
+}
+
+type LinkView struct {
+	Name string
+	URL  string
+}
+
+// PostSummary has the co-authors next to the primary Author, editors and reviewers are in Credits.
+type PostSummary struct {
+	Title        string
+	URL          string
+	Excerpt      string
+	Author       LinkView
+	CoAuthors    []LinkView
+	Credits      []CreditView
+	Tags         []LinkView
+	CommentCount int64
+	Cover        *PostImageResponse
+	PublishedAt  *time.Time
+	UpdatedAt    *time.Time
+}
+
+// CreditView is a contributor other than an author, Label reads like "Edited by".
+type CreditView struct {
+	Label string
+	Name  string
+	URL   string
+}
+
+// PageLinks pages through a list, PrevURL and NextURL are empty on the first and last page.
+type PageLinks struct {
+	Page      int
+	TotalPage int
+	PrevURL   string
+	NextURL   string
+}
+
+// PostListPage is the home, tag, author and search page.
+type PostListPage struct {
+	SitePage
+	Heading    string
+	Intro      string
+	Query      string
+	Posts      []PostSummary
+	Pagination PageLinks
+}
+
+// PostPage has a nil Series for a post that is not part of one.
+type PostPage struct {
+	SitePage
+	Post    PostSummary
+	Content template.HTML
+	Gallery []PostImageResponse
+	Series  *PostSeriesResponse
+}
+
+// SeriesPage lists the published parts of a series in reading order.
+type SeriesPage struct {
+	SitePage
+	Heading string
+	Intro   string
+	Author  LinkView
+	Posts   []PostSummary
+}
+
+type ArchivePage struct {
+	SitePage
+	Months []ArchiveMonth
+}
+
+type ArchiveMonth struct {
+	Label string
+	Posts []PostSummary
+}
+
+type ErrorPage struct {
+	SitePage
+	Code    int
+	Message string
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// SitemapEntry is a post slug, tag slug or author username with the last change of its page.
+type SitemapEntry struct {
+	Name    string
+	LastMod *time.Time
+}
+
+type SitemapIndex struct {
+	XMLName  xml.Name          `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []SitemapLocation `xml:"sitemap"`
+}
+
+type SitemapURLSet struct {
+	XMLName xml.Name          `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []SitemapLocation `xml:"url"`
+}
+
+type SitemapLocation struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// StaticExportResult counts the files of a static export, Unchanged files were left as they were.
+type StaticExportResult struct {
+	Written   int
+	Unchanged int
+	Removed   int
+}
@@ -5,18 +5,45 @@ import (
 )
 
 type UserResponse struct {
-	ID        string     `json:"id,omitempty"`
-	Name      string     `json:"name,omitempty"`
-	Email     string     `json:"email,omitempty"`
-	Username  string     `json:"username,omitempty"`
-	Token     string     `json:"token,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	ID           string       `json:"id,omitempty"`
+	Name         string       `json:"name,omitempty"`
+	Email        string       `json:"email,omitempty"`
+	Username     string       `json:"username,omitempty"`
+	Bio          string       `json:"bio,omitempty"`
+	AvatarURL    string       `json:"avatar_url,omitempty"`
+	Website      string       `json:"website,omitempty"`
+	SocialLinks  *SocialLinks `json:"social_links,omitempty"`
+	PendingEmail string       `json:"pending_email,omitempty"`
+	Token        string       `json:"token,omitempty"`
+	CreatedAt    *time.Time   `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time   `json:"updated_at,omitempty"`
+}
+
+type SocialLinks struct {
+	Twitter  string `json:"twitter,omitempty" validate:"max=100"`
+	Github   string `json:"github,omitempty" validate:"max=100"`
+	Linkedin string `json:"linkedin,omitempty" validate:"max=100"`
+	Mastodon string `json:"mastodon,omitempty" validate:"max=100"`
+}
+
+// ProfileResponse is the public view of a user, it never includes the email.
+type ProfileResponse struct {
+	Name           string         `json:"name"`
+	Username       string         `json:"username"`
+	Bio            string         `json:"bio,omitempty"`
+	AvatarURL      string         `json:"avatar_url,omitempty"`
+	Website        string         `json:"website,omitempty"`
+	SocialLinks    *SocialLinks   `json:"social_links,omitempty"`
+	PostCount      int64          `json:"post_count"`
+	FollowerCount  int64          `json:"follower_count"`
+	FollowingCount int64          `json:"following_count"`
+	LatestPosts    []PostResponse `json:"latest_posts"`
+	CreatedAt      *time.Time     `json:"created_at,omitempty"`
 }
 
 type RegisterUserRequest struct {
 	Name     string `json:"name" validate:"required,max=100"`
-	Username string `json:"username" validate:"required,min=5,max=30"`
+	Username string `json:"username" validate:"required,min=5,max=30,validateUsername"`
 	Email    string `json:"email" validate:"required,email,max=255"`
 	Password string `json:"password" validate:"required,max=100"`
 }
@@ -24,11 +51,50 @@ type RegisterUserRequest struct {
 type LoginUserRequest struct {
 	Email    string `json:"email" validate:"required,email,max=255"`
 	Password string `json:"password" validate:"required,max=100"`
+	IP       string `json:"-"`
+}
+
+// DeleteUserRequest decides what happens to the posts of the deleted user, Password is only
+// checked when users delete their own account.
+type DeleteUserRequest struct {
+	ID         string `json:"-" validate:"uuid4"`
+	Password   string `json:"password,omitempty" validate:"max=100"`
+	Posts      string `json:"posts" validate:"required,oneof=transfer anonymize delete"`
+	TransferTo string `json:"transfer_to,omitempty" validate:"required_if=Posts transfer,max=30"`
+}
+
+type DeleteUserResponse struct {
+	ID         string     `json:"id"`
+	Posts      string     `json:"posts"`
+	PurgeAfter *time.Time `json:"purge_after"`
+}
+
+type ConfirmEmailRequest struct {
+	Token string `json:"token" validate:"required,len=64,hexadecimal"`
 }
 
+type UnlockLoginRequest struct {
+	Email string `json:"email,omitempty" validate:"omitempty,email,max=255"`
+	IP    string `json:"ip,omitempty" validate:"omitempty,ip"`
+}
+
+// UpdateUserRequest leaves out the fields that are not sent, the profile fields are pointers so
+// sending an empty string clears them.
 type UpdateUserRequest struct {
-	ID       string `json:"-" validate:"uuid4"`
-	Name     string `json:"name,omitempty" validate:"max=100"`
-	Email    string `json:"email,omitempty" validate:"omitempty,validateEmail,max=255"`
-	Password string `json:"password,omitempty" validate:"max=100"`
+	ID          string                    `json:"-" validate:"uuid4"`
+	Name        string                    `json:"name,omitempty" validate:"max=100"`
+	Username    string                    `json:"username,omitempty" validate:"omitempty,min=5,max=30,validateUsername"`
+	Email       string                    `json:"email,omitempty" validate:"omitempty,validateEmail,max=255"`
+	Password    string                    `json:"password,omitempty" validate:"max=100"`
+	Bio         *string                   `json:"bio,omitempty" validate:"omitempty,max=500"`
+	AvatarURL   *string                   `json:"avatar_url,omitempty" validate:"omitempty,url,max=255"`
+	Website     *string                   `json:"website,omitempty" validate:"omitempty,url,max=255"`
+	SocialLinks *UpdateSocialLinksRequest `json:"social_links,omitempty"`
+}
+
+type UpdateSocialLinksRequest struct {
+	Twitter  *string `json:"twitter,omitempty" validate:"omitempty,max=100"`
+	Github   *string `json:"github,omitempty" validate:"omitempty,max=100"`
+	Linkedin *string `json:"linkedin,omitempty" validate:"omitempty,max=100"`
+	Mastodon *string `json:"mastodon,omitempty" validate:"omitempty,max=100"`
 }
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+type AccountDeletionRepository struct {
+	Repository[entity.AccountDeletion]
+	Log *logrus.Logger
+}
+
+func NewAccountDeletionRepository(log *logrus.Logger) *AccountDeletionRepository {
+	return &AccountDeletionRepository{
+		Log: log,
+	}
+}
+
+func (r *AccountDeletionRepository) FindByUserId(db *gorm.DB, deletion *entity.AccountDeletion, userId string) error {
+	return db.Where("user_id = ?", userId).Take(deletion).Error
+}
+
+func (r *AccountDeletionRepository) FindPurgeable(db *gorm.DB, now time.Time) ([]entity.AccountDeletion, error) {
+	var deletions []entity.AccountDeletion
+	err := db.Where("purge_after <= ?", now).Find(&deletions).Error
+	return deletions, err
+}
+
+func (r *AccountDeletionRepository) DeleteByUserId(db *gorm.DB, userId string) error {
+	return db.Where("user_id = ?", userId).Delete(&entity.AccountDeletion{}).Error
+}
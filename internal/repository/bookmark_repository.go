@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkRepository struct {
+	Repository[entity.Bookmark]
+	Log *logrus.Logger
+}
+
+func NewBookmarkRepository(log *logrus.Logger) *BookmarkRepository {
+	return &BookmarkRepository{
+		Log: log,
+	}
+}
+
+// Add ignores bookmarks that already exist, so bookmarking twice is not an error.
+func (r *BookmarkRepository) Add(db *gorm.DB, bookmark *entity.Bookmark) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(bookmark).Error
+}
+
+func (r *BookmarkRepository) Remove(db *gorm.DB, userId string, postId uint) error {
+	return db.Where("user_id = ? AND post_id = ?", userId, postId).Delete(&entity.Bookmark{}).Error
+}
+
+// FindPostIdsByUser returns which of postIds the user bookmarked.
+func (r *BookmarkRepository) FindPostIdsByUser(db *gorm.DB, userId string, postIds []uint) (map[uint]bool, error) {
+	bookmarked := make(map[uint]bool)
+	if userId == "" || len(postIds) == 0 {
+		return bookmarked, nil
+	}
+
+	var ids []uint
+	err := db.Model(&entity.Bookmark{}).
+		Where("user_id = ? AND post_id IN ?", userId, postIds).
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		bookmarked[id] = true
+	}
+	return bookmarked, nil
+}
+
+// HardDeleteByUserId removes the bookmarks of a user and the bookmarks others made on the user's posts.
+func (r *BookmarkRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	postIds := db.Unscoped().Model(&entity.Post{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Where("post_id IN (?)", postIds).Delete(&entity.Bookmark{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userId).Delete(&entity.Bookmark{}).Error
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"gorm.io/gorm"
+	"time"
+)
+
+type CommentRepository struct {
+	Repository[entity.Comment]
+	Log *logrus.Logger
+}
+
+func NewCommentRepository(log *logrus.Logger) *CommentRepository {
+	return &CommentRepository{
+		Log: log,
+	}
+}
+
+func preloadCommentUser(db *gorm.DB) *gorm.DB {
+	return db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Select("ID", "Name", "Username")
+	})
+}
+
+func (r *CommentRepository) FindByIdWithUser(db *gorm.DB, comment *entity.Comment, id any) error {
+	return preloadCommentUser(db).Where("id = ?", id).Take(comment).Error
+}
+
+// FindFlat returns a page of the visible comments of a post, oldest first.
+func (r *CommentRepository) FindFlat(db *gorm.DB, postId uint, paginate model.Pagination) ([]entity.Comment, int64, error) {
+	var comments []entity.Comment
+	err := preloadCommentUser(db).
+		Where("post_id = ? AND status = ?", postId, entity.CommentStatusApproved).
+		Order("created_at asc, id asc").
+		Offset((paginate.Page - 1) * paginate.Size).
+		Limit(paginate.Size).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := db.Model(&entity.Comment{}).Where("post_id = ? AND status = ?", postId, entity.CommentStatusApproved).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// FindRoots returns a page of top level comments, deleted or unapproved ones are kept while they still have visible replies.
+func (r *CommentRepository) FindRoots(db *gorm.DB, postId uint, paginate model.Pagination) ([]entity.Comment, int64, error) {
+	scope := func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped().
+			Where("comments.post_id = ? AND comments.parent_id IS NULL", postId).
+			Where("(comments.deleted_at IS NULL AND comments.status = ?) OR EXISTS (SELECT 1 FROM comments replies WHERE replies.root_id = comments.id AND replies.deleted_at IS NULL AND replies.status = ?)",
+				entity.CommentStatusApproved, entity.CommentStatusApproved)
+	}
+
+	var comments []entity.Comment
+	err := preloadCommentUser(db).
+		Scopes(scope).
+		Order("comments.created_at asc, comments.id asc").
+		Offset((paginate.Page - 1) * paginate.Size).
+		Limit(paginate.Size).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := db.Model(&entity.Comment{}).Scopes(scope).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// FindByRootIds returns every reply in the given threads, deleted ones included.
+func (r *CommentRepository) FindByRootIds(db *gorm.DB, rootIds []uint) ([]entity.Comment, error) {
+	var comments []entity.Comment
+	if len(rootIds) == 0 {
+		return comments, nil
+	}
+	err := preloadCommentUser(db).
+		Unscoped().
+		Where("root_id IN ?", rootIds).
+		Order("created_at asc, id asc").
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *CommentRepository) FindLatestByUserId(db *gorm.DB, comment *entity.Comment, userId string) error {
+	return db.Unscoped().Where("user_id = ?", userId).Order("id desc").Take(comment).Error
+}
+
+func (r *CommentRepository) ExistsDuplicate(db *gorm.DB, userId string, content string, since time.Time) (bool, error) {
+	var total int64
+	err := db.Unscoped().Model(&entity.Comment{}).
+		Where("user_id = ? AND content = ? AND created_at > ?", userId, content, since).
+		Count(&total).Error
+	return total > 0, err
+}
+
+func (r *CommentRepository) CountApprovedByUserId(db *gorm.DB, userId string) (int64, error) {
+	var total int64
+	err := db.Model(&entity.Comment{}).
+		Where("user_id = ? AND status = ?", userId, entity.CommentStatusApproved).
+		Count(&total).Error
+	return total, err
+}
+
+// FindForModeration returns a page of comments in a status, limited to the posts of PostOwnerID when it is set.
+func (r *CommentRepository) FindForModeration(db *gorm.DB, request *model.SearchModerationRequest) ([]entity.Comment, int64, error) {
+	scope := func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("comments.status = ?", request.Status)
+		if request.PostOwnerID != "" {
+			tx = tx.Joins("inner join posts p on p.id = comments.post_id").
+				Where("p.user_id = ? AND p.deleted_at IS NULL", request.PostOwnerID)
+		}
+		return tx
+	}
+
+	var comments []entity.Comment
+	err := preloadCommentUser(db).
+		Preload("Post", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Title", "Slug", "UserID")
+		}).
+		Scopes(scope).
+		Order("comments.created_at asc, comments.id asc").
+		Offset((request.Paginate.Page - 1) * request.Paginate.Size).
+		Limit(request.Paginate.Size).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := db.Model(&entity.Comment{}).Scopes(scope).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// DeleteByUserId soft deletes the comments of a user with deletedAt, so RestoreByUserId can bring back exactly those comments.
+func (r *CommentRepository) DeleteByUserId(db *gorm.DB, userId string, deletedAt time.Time) error {
+	return db.Model(&entity.Comment{}).Where("user_id = ?", userId).UpdateColumn("deleted_at", deletedAt).Error
+}
+
+func (r *CommentRepository) RestoreByUserId(db *gorm.DB, userId string, deletedAt time.Time) error {
+	return db.Unscoped().Model(&entity.Comment{}).
+		Where("user_id = ? AND deleted_at = ?", userId, deletedAt).
+		UpdateColumn("deleted_at", nil).Error
+}
+
+// HardDeleteByUserId removes the comments written by a user and every comment on the user's posts.
+func (r *CommentRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	postIds := db.Unscoped().Model(&entity.Post{}).Select("id").Where("user_id = ?", userId)
+	return db.Unscoped().
+		Where("user_id = ? OR post_id IN (?)", userId, postIds).
+		Delete(&entity.Comment{}).Error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+type DataExportRepository struct {
+	Repository[entity.DataExport]
+	Log *logrus.Logger
+}
+
+func NewDataExportRepository(log *logrus.Logger) *DataExportRepository {
+	return &DataExportRepository{
+		Log: log,
+	}
+}
+
+func (r *DataExportRepository) FindByIdAndUserId(db *gorm.DB, export *entity.DataExport, id string, userId string) error {
+	return db.Where("id = ? AND user_id = ?", id, userId).Take(export).Error
+}
+
+func (r *DataExportRepository) FindPendingByUserId(db *gorm.DB, export *entity.DataExport, userId string) error {
+	return db.Where("user_id = ? AND status = ?", userId, entity.DataExportStatusPending).Take(export).Error
+}
+
+func (r *DataExportRepository) FindExpired(db *gorm.DB, now time.Time) ([]entity.DataExport, error) {
+	var exports []entity.DataExport
+	err := db.Where("expires_at <= ?", now).Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepository) FindStalePending(db *gorm.DB, before time.Time) ([]entity.DataExport, error) {
+	var exports []entity.DataExport
+	err := db.Where("status = ? AND created_at <= ?", entity.DataExportStatusPending, before).Find(&exports).Error
+	return exports, err
+}
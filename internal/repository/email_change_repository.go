@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+type EmailChangeRepository struct {
+	Repository[entity.EmailChange]
+	Log *logrus.Logger
+}
+
+func NewEmailChangeRepository(log *logrus.Logger) *EmailChangeRepository {
+	return &EmailChangeRepository{
+		Log: log,
+	}
+}
+
+func (r *EmailChangeRepository) FindByTokenHash(db *gorm.DB, emailChange *entity.EmailChange, tokenHash string) error {
+	return db.Where("token_hash = ?", tokenHash).Take(emailChange).Error
+}
+
+func (r *EmailChangeRepository) FindPendingByUserId(db *gorm.DB, emailChange *entity.EmailChange, userId string) error {
+	return db.Where("user_id = ? AND expires_at > ?", userId, time.Now()).Order("id desc").Take(emailChange).Error
+}
+
+func (r *EmailChangeRepository) DeleteByUserId(db *gorm.DB, userId string) error {
+	return db.Where("user_id = ?", userId).Delete(&entity.EmailChange{}).Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository struct {
+	Repository[entity.UserFollow]
+	Log *logrus.Logger
+}
+
+func NewFollowRepository(log *logrus.Logger) *FollowRepository {
+	return &FollowRepository{
+		Log: log,
+	}
+}
+
+// FollowUser ignores follows that already exist, so following twice is not an error.
+func (r *FollowRepository) FollowUser(db *gorm.DB, followerId string, followeeId string) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.UserFollow{FollowerID: followerId, FolloweeID: followeeId}).Error
+}
+
+func (r *FollowRepository) UnfollowUser(db *gorm.DB, followerId string, followeeId string) error {
+	return db.Where("follower_id = ? AND followee_id = ?", followerId, followeeId).Delete(&entity.UserFollow{}).Error
+}
+
+func (r *FollowRepository) FollowTag(db *gorm.DB, userId string, tagId uint) error {
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.TagFollow{UserID: userId, TagID: tagId}).Error
+}
+
+func (r *FollowRepository) UnfollowTag(db *gorm.DB, userId string, tagId uint) error {
+	return db.Where("user_id = ? AND tag_id = ?", userId, tagId).Delete(&entity.TagFollow{}).Error
+}
+
+// CountFollows returns how many users follow userId and how many users userId follows.
+func (r *FollowRepository) CountFollows(db *gorm.DB, userId string) (followers int64, following int64, err error) {
+	if err = db.Model(&entity.UserFollow{}).Where("followee_id = ?", userId).Count(&followers).Error; err != nil {
+		return 0, 0, err
+	}
+	if err = db.Model(&entity.UserFollow{}).Where("follower_id = ?", userId).Count(&following).Error; err != nil {
+		return 0, 0, err
+	}
+	return followers, following, nil
+}
+
+func (r *FollowRepository) FindFollowedUsers(db *gorm.DB, userId string) ([]entity.User, error) {
+	var users []entity.User
+	err := db.Select("users.id", "users.name", "users.username").
+		Joins("inner join user_follows f on f.followee_id = users.id").
+		Where("f.follower_id = ?", userId).
+		Order("f.created_at desc").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *FollowRepository) FindFollowedTags(db *gorm.DB, userId string) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	err := db.Select("tags.id", "tags.name", "tags.slug").
+		Joins("inner join tag_follows f on f.tag_id = tags.id").
+		Where("f.user_id = ?", userId).
+		Order("f.created_at desc").
+		Find(&tags).Error
+	return tags, err
+}
+
+func (r *FollowRepository) IsFollowingUser(db *gorm.DB, followerId string, followeeId string) (bool, error) {
+	var total int64
+	err := db.Model(&entity.UserFollow{}).Where("follower_id = ? AND followee_id = ?", followerId, followeeId).Count(&total).Error
+	return total > 0, err
+}
+
+// HardDeleteByUserId removes the follows made by the user and the follows on the user.
+func (r *FollowRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	if err := db.Where("follower_id = ? OR followee_id = ?", userId, userId).Delete(&entity.UserFollow{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userId).Delete(&entity.TagFollow{}).Error
+}
+
+func (r *FollowRepository) FindFollowerIds(db *gorm.DB, userId string) ([]string, error) {
+	var ids []string
+	err := db.Model(&entity.UserFollow{}).Where("followee_id = ?", userId).Pluck("follower_id", &ids).Error
+	return ids, err
+}
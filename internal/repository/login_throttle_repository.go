@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepository struct {
+	Repository[entity.LoginThrottle]
+	Log *logrus.Logger
+}
+
+func NewLoginThrottleRepository(log *logrus.Logger) *LoginThrottleRepository {
+	return &LoginThrottleRepository{
+		Log: log,
+	}
+}
+
+func (r *LoginThrottleRepository) FindByIdentifier(db *gorm.DB, throttle *entity.LoginThrottle, scope string, identifier string) error {
+	return db.Where("scope = ? AND identifier = ?", scope, identifier).Take(throttle).Error
+}
+
+func (r *LoginThrottleRepository) FindByIdentifierForUpdate(db *gorm.DB, throttle *entity.LoginThrottle, scope string, identifier string) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("scope = ? AND identifier = ?", scope, identifier).
+		Take(throttle).Error
+}
+
+func (r *LoginThrottleRepository) DeleteByIdentifier(db *gorm.DB, scope string, identifier string) error {
+	return db.Where("scope = ? AND identifier = ?", scope, identifier).Delete(&entity.LoginThrottle{}).Error
+}
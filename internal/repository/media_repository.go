@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"gorm.io/gorm"
+)
+
+type MediaRepository struct {
+	Repository[entity.Media]
+	Log *logrus.Logger
+}
+
+func NewMediaRepository(log *logrus.Logger) *MediaRepository {
+	return &MediaRepository{
+		Log: log,
+	}
+}
+
+// preloadVariants loads the variants smallest first, the order of a srcset.
+func preloadVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("width asc")
+}
+
+func (r *MediaRepository) FindByIdAndUserId(db *gorm.DB, media *entity.Media, id string, userId string) error {
+	return db.Preload("Variants", preloadVariants).Where("id = ? AND user_id = ?", id, userId).Take(media).Error
+}
+
+func (r *MediaRepository) Find(db *gorm.DB, request *model.SearchMediaRequest) ([]entity.Media, int64, error) {
+	query := db.Model(&entity.Media{}).Where("user_id = ?", request.UserId)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var media []entity.Media
+	err := query.
+		Preload("Variants", preloadVariants).
+		Order("created_at desc").
+		Order("id desc").
+		Offset((request.Paginate.Page - 1) * request.Paginate.Size).
+		Limit(request.Paginate.Size).
+		Find(&media).Error
+	return media, total, err
+}
+
+func (r *MediaRepository) DeleteVariants(db *gorm.DB, mediaId string) error {
+	return db.Where("media_id = ?", mediaId).Delete(&entity.MediaVariant{}).Error
+}
+
+func (r *MediaRepository) FindByIdsAndUserId(db *gorm.DB, ids []string, userId string) ([]entity.Media, error) {
+	var media []entity.Media
+	err := db.Preload("Variants", preloadVariants).Where("id IN ? AND user_id = ?", ids, userId).Find(&media).Error
+	return media, err
+}
+
+// CountUsage counts the posts, deleted ones included, that use the media as their cover or in their gallery.
+func (r *MediaRepository) CountUsage(db *gorm.DB, id string) (int64, error) {
+	var total int64
+	err := db.Model(&entity.Post{}).Unscoped().
+		Where("cover_media_id = ? OR id IN (?)", id, db.Model(&entity.PostImage{}).Select("post_id").Where("media_id = ?", id)).
+		Count(&total).Error
+	return total, err
+}
+
+func (r *MediaRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.Media, error) {
+	var media []entity.Media
+	err := db.Preload("Variants", preloadVariants).Where("user_id = ?", userId).Find(&media).Error
+	return media, err
+}
+
+// FindOtherUserId returns the owner of a post, deleted ones included, using the media as its cover or in its
+// gallery while not belonging to userId, or an empty string when there is none.
+func (r *MediaRepository) FindOtherUserId(db *gorm.DB, id string, userId string) (string, error) {
+	var userIds []string
+	err := db.Model(&entity.Post{}).Unscoped().
+		Where("cover_media_id = ? OR id IN (?)", id, db.Model(&entity.PostImage{}).Select("post_id").Where("media_id = ?", id)).
+		Where("user_id <> ?", userId).
+		Order("id asc").
+		Limit(1).
+		Pluck("user_id", &userIds).Error
+	if err != nil || len(userIds) == 0 {
+		return "", err
+	}
+	return userIds[0], nil
+}
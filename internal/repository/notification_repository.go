@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type NotificationRepository struct {
+	Repository[entity.Notification]
+	Log *logrus.Logger
+}
+
+func NewNotificationRepository(log *logrus.Logger) *NotificationRepository {
+	return &NotificationRepository{
+		Log: log,
+	}
+}
+
+func (r *NotificationRepository) CreateAll(db *gorm.DB, notifications []*entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return db.CreateInBatches(notifications, 100).Error
+}
+
+func (r *NotificationRepository) Find(db *gorm.DB, request *model.SearchNotificationRequest) ([]entity.Notification, int64, error) {
+	query := db.Model(&entity.Notification{}).Where("user_id = ?", request.UserId)
+	if request.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []entity.Notification
+	err := query.
+		Order("id desc").
+		Offset((request.Paginate.Page - 1) * request.Paginate.Size).
+		Limit(request.Paginate.Size).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *NotificationRepository) CountUnread(db *gorm.DB, userId string) (int64, error) {
+	var total int64
+	err := db.Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&total).Error
+	return total, err
+}
+
+func (r *NotificationRepository) FindByIdAndUserId(db *gorm.DB, notification *entity.Notification, id uint, userId string) error {
+	return db.Where("id = ? AND user_id = ?", id, userId).Take(notification).Error
+}
+
+func (r *NotificationRepository) MarkAllRead(db *gorm.DB, userId string, readAt time.Time) (int64, error) {
+	result := db.Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userId).
+		UpdateColumn("read_at", readAt)
+	return result.RowsAffected, result.Error
+}
+
+// FindDisabledUserIds returns which of userIds turned notifications of this type off.
+func (r *NotificationRepository) FindDisabledUserIds(db *gorm.DB, notificationType string, userIds []string) (map[string]bool, error) {
+	disabled := make(map[string]bool)
+	if len(userIds) == 0 {
+		return disabled, nil
+	}
+
+	var ids []string
+	err := db.Model(&entity.NotificationPreference{}).
+		Where("type = ? AND enabled = ? AND user_id IN ?", notificationType, false, userIds).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		disabled[id] = true
+	}
+	return disabled, nil
+}
+
+func (r *NotificationRepository) FindPreferences(db *gorm.DB, userId string) ([]entity.NotificationPreference, error) {
+	var preferences []entity.NotificationPreference
+	err := db.Where("user_id = ?", userId).Find(&preferences).Error
+	return preferences, err
+}
+
+func (r *NotificationRepository) SavePreference(db *gorm.DB, preference *entity.NotificationPreference) error {
+	return db.Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "email"}),
+	}).Create(preference).Error
+}
+
+// FindDigestUserIds returns the users with unread notifications they want by email and did not get yet.
+func (r *NotificationRepository) FindDigestUserIds(db *gorm.DB) ([]string, error) {
+	var ids []string
+	err := db.Model(&entity.Notification{}).
+		Distinct("notifications.user_id").
+		Joins("inner join notification_preferences np on np.user_id = notifications.user_id and np.type = notifications.type").
+		Where("np.email = ? AND notifications.read_at IS NULL AND notifications.emailed_at IS NULL", true).
+		Pluck("notifications.user_id", &ids).Error
+	return ids, err
+}
+
+func (r *NotificationRepository) FindDigest(db *gorm.DB, userId string) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	err := db.
+		Joins("inner join notification_preferences np on np.user_id = notifications.user_id and np.type = notifications.type").
+		Where("notifications.user_id = ? AND np.email = ? AND notifications.read_at IS NULL AND notifications.emailed_at IS NULL", userId, true).
+		Order("notifications.id asc").
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *NotificationRepository) MarkEmailed(db *gorm.DB, ids []uint, emailedAt time.Time) error {
+	return db.Model(&entity.Notification{}).Where("id IN ?", ids).UpdateColumn("emailed_at", emailedAt).Error
+}
+
+func (r *NotificationRepository) DeleteByUserId(db *gorm.DB, userId string) error {
+	if err := db.Where("user_id = ?", userId).Delete(&entity.Notification{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userId).Delete(&entity.NotificationPreference{}).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+)
+
+type PostAuthorRepository struct {
+	Repository[entity.PostAuthor]
+	Log *logrus.Logger
+}
+
+func NewPostAuthorRepository(log *logrus.Logger) *PostAuthorRepository {
+	return &PostAuthorRepository{
+		Log: log,
+	}
+}
+
+func (r *PostAuthorRepository) FindByPostIdAndUserId(db *gorm.DB, author *entity.PostAuthor, postId uint, userId string) error {
+	return db.Where("post_id = ? AND user_id = ?", postId, userId).Take(author).Error
+}
+
+// FindAllByPostId returns the contributors of a post in invitation order, pending ones included.
+func (r *PostAuthorRepository) FindAllByPostId(db *gorm.DB, postId uint) ([]entity.PostAuthor, error) {
+	var authors []entity.PostAuthor
+	err := db.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name", "Username")
+		}).
+		Where("post_id = ?", postId).
+		Order("id asc").
+		Find(&authors).Error
+	return authors, err
+}
+
+// FindPendingByUserId returns the invitations the user has not answered yet, newest first.
+func (r *PostAuthorRepository) FindPendingByUserId(db *gorm.DB, userId string) ([]entity.PostAuthor, error) {
+	var authors []entity.PostAuthor
+	err := db.
+		Preload("Post", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Title", "Slug", "UserID")
+		}).
+		Preload("Post.User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name", "Username")
+		}).
+		Joins("inner join posts p on p.id = post_authors.post_id and p.deleted_at IS NULL").
+		Where("post_authors.user_id = ? AND post_authors.accepted_at IS NULL", userId).
+		Order("post_authors.id desc").
+		Find(&authors).Error
+	return authors, err
+}
+
+// HardDeleteByUserId removes the credits of a user and every credit on the user's posts.
+func (r *PostAuthorRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	postIds := db.Unscoped().Model(&entity.Post{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Where("post_id IN (?)", postIds).Delete(&entity.PostAuthor{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userId).Delete(&entity.PostAuthor{}).Error
+}
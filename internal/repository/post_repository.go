@@ -6,6 +6,7 @@ import (
 	"go-blog/internal/model"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"time"
 )
 
 type PostRepository struct {
@@ -19,16 +20,195 @@ func NewPostRepository(log *logrus.Logger) *PostRepository {
 	}
 }
 
-func (r *Repository[T]) Find(db *gorm.DB, request *model.SearchPostRequest) ([]entity.Post, int64, error) {
-	var posts []entity.Post
-
-	err := db.
+// preloadPost loads what PostToResponse needs next to the post itself.
+func preloadPost(db *gorm.DB) *gorm.DB {
+	return db.
 		Preload("Tags", func(db *gorm.DB) *gorm.DB {
 			return db.Select("ID", "Name", "Slug")
 		}).
 		Preload("User", func(db *gorm.DB) *gorm.DB {
 			return db.Select("ID", "Name", "Username")
 		}).
+		Preload("ReactionCounts", "count > 0").
+		Preload("CoverMedia.Variants", preloadVariants).
+		Preload("Authors", func(db *gorm.DB) *gorm.DB {
+			return db.Where("accepted_at IS NOT NULL").Order("id asc")
+		}).
+		Preload("Authors.User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name", "Username")
+		})
+}
+
+// ReplaceTags sets the tags of the post to tags, dropping the ones left out.
+func (r *PostRepository) ReplaceTags(db *gorm.DB, post *entity.Post, tags []*entity.Tag) error {
+	return db.Model(post).Association("Tags").Replace(tags)
+}
+
+func (r *PostRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.Post, error) {
+	var posts []entity.Post
+	err := db.
+		Preload("Tags", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name", "Slug")
+		}).
+		Where("user_id = ?", userId).
+		Order("created_at asc").
+		Find(&posts).Error
+	return posts, err
+}
+
+// IncrementCommentCount skips the autoUpdateTime so a comment does not look like a post edit.
+func (r *PostRepository) IncrementCommentCount(db *gorm.DB, postId uint, delta int) error {
+	return db.Model(&entity.Post{}).Where("id = ?", postId).
+		UpdateColumn("comment_count", gorm.Expr("GREATEST(comment_count + ?, 0)", delta)).Error
+}
+
+// RecountCommentsByCommenter recomputes the approved comment counts of every post the user commented on.
+func (r *PostRepository) RecountCommentsByCommenter(db *gorm.DB, userId string) error {
+	return db.Exec(`UPDATE posts SET comment_count = (
+		SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL AND comments.status = ?
+	) WHERE id IN (SELECT post_id FROM comments WHERE user_id = ?)`, entity.CommentStatusApproved, userId).Error
+}
+
+// FindByReadingListId returns the posts of a reading list in list order.
+func (r *PostRepository) FindByReadingListId(db *gorm.DB, listId uint) ([]entity.Post, error) {
+	var posts []entity.Post
+	err := db.
+		Scopes(preloadPost).
+		Joins("inner join reading_list_items rli on rli.post_id = posts.id").
+		Where("rli.reading_list_id = ?", listId).
+		Order("rli.position asc").
+		Find(&posts).Error
+	return posts, err
+}
+
+// FindBySeriesId returns the parts of a series in order, publishedOnly leaves out the ones readers cannot see yet.
+func (r *PostRepository) FindBySeriesId(db *gorm.DB, seriesId uint, publishedOnly bool) ([]entity.Post, error) {
+	query := db.
+		Scopes(preloadPost).
+		Joins("inner join series_posts sp on sp.post_id = posts.id").
+		Where("sp.series_id = ?", seriesId)
+	if publishedOnly {
+		query = query.Where("posts.published_at IS NOT NULL AND posts.published_at <= ?", time.Now())
+	}
+
+	var posts []entity.Post
+	err := query.Order("sp.position asc").Find(&posts).Error
+	return posts, err
+}
+
+// FindSeriesParts returns the id, title and slug of the published parts of a series in order.
+func (r *PostRepository) FindSeriesParts(db *gorm.DB, seriesId uint) ([]entity.Post, error) {
+	var posts []entity.Post
+	err := db.
+		Select("posts.id", "posts.title", "posts.slug").
+		Joins("inner join series_posts sp on sp.post_id = posts.id").
+		Where("sp.series_id = ?", seriesId).
+		Where("posts.published_at IS NOT NULL AND posts.published_at <= ?", time.Now()).
+		Order("sp.position asc").
+		Find(&posts).Error
+	return posts, err
+}
+
+// FindFeed returns the published posts of the authors and tags the user follows, newest first.
+// A post matching several follows comes once, and request.Before* continue after the last post of the previous page.
+// It loads one post more than request.Size so the caller can tell whether there is a next page.
+func (r *PostRepository) FindFeed(db *gorm.DB, request *model.FeedRequest) ([]entity.Post, error) {
+	followedAuthors := db.Model(&entity.UserFollow{}).Select("followee_id").Where("follower_id = ?", request.UserId)
+	followedTags := db.Table("post_tags pt").
+		Select("pt.post_id").
+		Joins("inner join tag_follows tf on tf.tag_id = pt.tag_id").
+		Where("tf.user_id = ?", request.UserId)
+
+	query := db.
+		Scopes(preloadPost).
+		Where("posts.published_at IS NOT NULL AND posts.published_at <= ?", time.Now()).
+		Where(db.Where("posts.user_id IN (?)", followedAuthors).Or("posts.id IN (?)", followedTags))
+	if request.BeforePublishedAt != nil {
+		query = query.Where("posts.published_at < ? OR (posts.published_at = ? AND posts.id < ?)",
+			request.BeforePublishedAt, request.BeforePublishedAt, request.BeforeID)
+	}
+
+	var posts []entity.Post
+	err := query.
+		Order("posts.published_at desc").
+		Order("posts.id desc").
+		Limit(request.Size + 1).
+		Find(&posts).Error
+	return posts, err
+}
+
+// FindArchive returns the title, slug and publication date of every published post, newest first.
+func (r *PostRepository) FindArchive(db *gorm.DB) ([]entity.Post, error) {
+	var posts []entity.Post
+	err := db.
+		Select("id", "title", "slug", "published_at").
+		Where("published_at IS NOT NULL AND published_at <= ?", time.Now()).
+		Order("published_at desc").
+		Order("id desc").
+		Find(&posts).Error
+	return posts, err
+}
+
+// FindSitemapEntries returns the slug and last update of every published post that may be indexed, oldest first.
+func (r *PostRepository) FindSitemapEntries(db *gorm.DB) ([]model.SitemapEntry, error) {
+	var entries []model.SitemapEntry
+	err := db.Model(&entity.Post{}).
+		Select("slug AS name, updated_at AS last_mod").
+		Where("published_at IS NOT NULL AND published_at <= ?", time.Now()).
+		Where("no_index = ?", false).
+		Order("id asc").
+		Scan(&entries).Error
+	return entries, err
+}
+
+// SitemapVersion changes whenever a post, user, tag or series is created, updated, deleted or published,
+// so a generated sitemap can be kept until then.
+func (r *PostRepository) SitemapVersion(db *gorm.DB) (string, error) {
+	var version string
+	err := db.Raw(`SELECT CONCAT_WS(':',
+		(SELECT COUNT(*) FROM posts),
+		(SELECT COUNT(*) FROM posts WHERE deleted_at IS NULL AND published_at <= ?),
+		(SELECT MAX(updated_at) FROM posts),
+		(SELECT COUNT(*) FROM users),
+		(SELECT MAX(updated_at) FROM users),
+		(SELECT COUNT(*) FROM tags),
+		(SELECT COUNT(*) FROM series),
+		(SELECT MAX(updated_at) FROM series))`, time.Now()).
+		Scan(&version).Error
+	return version, err
+}
+
+func (r *PostRepository) TransferByUserId(db *gorm.DB, fromUserId string, toUserId string) error {
+	return db.Model(&entity.Post{}).Where("user_id = ?", fromUserId).Update("user_id", toUserId).Error
+}
+
+// DeleteByUserId soft deletes the posts of a user with deletedAt, so RestoreByUserId can bring back exactly those posts.
+func (r *PostRepository) DeleteByUserId(db *gorm.DB, userId string, deletedAt time.Time) error {
+	return db.Model(&entity.Post{}).Where("user_id = ?", userId).Update("deleted_at", deletedAt).Error
+}
+
+func (r *PostRepository) RestoreByUserId(db *gorm.DB, userId string, deletedAt time.Time) error {
+	return db.Unscoped().Model(&entity.Post{}).
+		Where("user_id = ? AND deleted_at = ?", userId, deletedAt).
+		Update("deleted_at", nil).Error
+}
+
+func (r *PostRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	postIds := db.Unscoped().Model(&entity.Post{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Exec("DELETE FROM post_tags WHERE post_id IN (?)", postIds).Error; err != nil {
+		return err
+	}
+	if err := db.Where("post_id IN (?)", postIds).Delete(&entity.PostImage{}).Error; err != nil {
+		return err
+	}
+	return db.Unscoped().Where("user_id = ?", userId).Delete(&entity.Post{}).Error
+}
+
+func (r *Repository[T]) Find(db *gorm.DB, request *model.SearchPostRequest) ([]entity.Post, int64, error) {
+	var posts []entity.Post
+
+	err := db.
+		Scopes(preloadPost).
 		Scopes(r.filterPostScopes(request)).
 		Offset((request.Paginate.Page - 1) * request.Paginate.Size).
 		Limit(request.Paginate.Size).
@@ -49,22 +229,24 @@ func (r *Repository[T]) Find(db *gorm.DB, request *model.SearchPostRequest) ([]e
 	return posts, total, nil
 }
 
+// FindBySlug also loads the gallery, the lists only carry the cover.
 func (r *Repository[T]) FindBySlug(db *gorm.DB, entity *T, slug string) error {
 	return db.
 		Where("slug = ?", slug).
-		Preload("Tags", func(db *gorm.DB) *gorm.DB {
-			return db.Select("ID", "Name", "Slug")
+		Scopes(preloadPost).
+		Preload("Gallery", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc")
 		}).
-		Preload("User", func(db *gorm.DB) *gorm.DB {
-			return db.Select("ID", "Name", "Username")
-		}).Take(entity).Error
+		Preload("Gallery.Media.Variants", preloadVariants).
+		Take(entity).Error
 }
 func (r *Repository[T]) filterPostScopes(request *model.SearchPostRequest) func(tx *gorm.DB) *gorm.DB {
 	return func(tx *gorm.DB) *gorm.DB {
+		// The posts of a user include the ones they accepted a credit on.
 		if username := request.Username; username != "" {
-			tx = tx.
-				Joins("inner join users u on u.id = posts.user_id").
-				Where("u.username = ?", username)
+			tx = tx.Where(`(posts.user_id IN (SELECT id FROM users WHERE username = ?) OR posts.id IN (
+				SELECT pa.post_id FROM post_authors pa INNER JOIN users u ON u.id = pa.user_id
+				WHERE u.username = ? AND pa.accepted_at IS NOT NULL))`, username, username)
 		}
 		if len(request.Tags) > 0 && request.Tags[0] != "" {
 			tx = tx.
@@ -73,6 +255,22 @@ func (r *Repository[T]) filterPostScopes(request *model.SearchPostRequest) func(
 				Where("t.slug IN ?", request.Tags)
 		}
 
+		if request.SeriesId != 0 {
+			tx = tx.
+				Joins("inner join series_posts sp on sp.post_id = posts.id").
+				Where("sp.series_id = ?", request.SeriesId)
+		}
+
+		if request.Bookmarked {
+			tx = tx.
+				Joins("inner join bookmarks b on b.post_id = posts.id").
+				Where("b.user_id = ?", request.ViewerId)
+		}
+
+		if request.Published {
+			tx = tx.Where("posts.published_at IS NOT NULL AND posts.published_at <= ?", time.Now())
+		}
+
 		if title := request.Title; title != "" {
 			title = "%" + title + "%"
 			tx = tx.Where("title LIKE ?", title)
@@ -84,6 +282,8 @@ func (r *Repository[T]) filterPostScopes(request *model.SearchPostRequest) func(
 				tx = tx.Order("created_at desc")
 			case "oldest":
 				tx = tx.Order("created_at asc")
+			case "published":
+				tx = tx.Order("posts.published_at desc").Order("posts.id desc")
 			default:
 				tx = tx.Order("created_at desc")
 			}
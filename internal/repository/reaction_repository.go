@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository struct {
+	Repository[entity.Reaction]
+	Log *logrus.Logger
+}
+
+func NewReactionRepository(log *logrus.Logger) *ReactionRepository {
+	return &ReactionRepository{
+		Log: log,
+	}
+}
+
+// Add stores the reaction and bumps the post count, added is false when the user already reacted with this type.
+func (r *ReactionRepository) Add(db *gorm.DB, reaction *entity.Reaction) (bool, error) {
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, r.incrementCount(db, reaction.PostID, reaction.Type, 1)
+}
+
+// Remove deletes the reaction and lowers the post count, removed is false when there was nothing to remove.
+func (r *ReactionRepository) Remove(db *gorm.DB, postId uint, userId string, reactionType string) (bool, error) {
+	result := db.Where("post_id = ? AND user_id = ? AND type = ?", postId, userId, reactionType).Delete(&entity.Reaction{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, r.incrementCount(db, postId, reactionType, -1)
+}
+
+func (r *ReactionRepository) FindCountsByPostId(db *gorm.DB, postId uint) ([]entity.PostReactionCount, error) {
+	var counts []entity.PostReactionCount
+	err := db.Where("post_id = ? AND count > 0", postId).Find(&counts).Error
+	return counts, err
+}
+
+// FindTypesByUser returns, per post, the reaction types the user left on it.
+func (r *ReactionRepository) FindTypesByUser(db *gorm.DB, userId string, postIds []uint) (map[uint][]string, error) {
+	types := make(map[uint][]string)
+	if userId == "" || len(postIds) == 0 {
+		return types, nil
+	}
+
+	var reactions []entity.Reaction
+	err := db.Select("PostID", "Type").
+		Where("user_id = ? AND post_id IN ?", userId, postIds).
+		Order("id asc").
+		Find(&reactions).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, reaction := range reactions {
+		types[reaction.PostID] = append(types[reaction.PostID], reaction.Type)
+	}
+	return types, nil
+}
+
+// HardDeleteByUserId removes the reactions left by a user and everything attached to the user's posts,
+// then recomputes the counts of the other posts the user reacted on.
+func (r *ReactionRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	postIds := db.Unscoped().Model(&entity.Post{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Where("post_id IN (?)", postIds).Delete(&entity.Reaction{}).Error; err != nil {
+		return err
+	}
+	if err := db.Where("post_id IN (?)", postIds).Delete(&entity.PostReactionCount{}).Error; err != nil {
+		return err
+	}
+
+	var reacted []uint
+	if err := db.Model(&entity.Reaction{}).Distinct("post_id").Where("user_id = ?", userId).Pluck("post_id", &reacted).Error; err != nil {
+		return err
+	}
+	if err := db.Where("user_id = ?", userId).Delete(&entity.Reaction{}).Error; err != nil {
+		return err
+	}
+	if len(reacted) == 0 {
+		return nil
+	}
+	return db.Exec(`UPDATE post_reaction_counts SET count = (
+		SELECT COUNT(*) FROM reactions WHERE reactions.post_id = post_reaction_counts.post_id AND reactions.type = post_reaction_counts.type
+	) WHERE post_id IN ?`, reacted).Error
+}
+
+func (r *ReactionRepository) incrementCount(db *gorm.DB, postId uint, reactionType string, delta int) error {
+	count := &entity.PostReactionCount{PostID: postId, Type: reactionType, Count: int64(delta)}
+	if delta < 0 {
+		count.Count = 0
+	}
+	return db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{"count": gorm.Expr("GREATEST(count + ?, 0)", delta)}),
+	}).Create(count).Error
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReadingListRepository struct {
+	Repository[entity.ReadingList]
+	Log *logrus.Logger
+}
+
+func NewReadingListRepository(log *logrus.Logger) *ReadingListRepository {
+	return &ReadingListRepository{
+		Log: log,
+	}
+}
+
+func (r *ReadingListRepository) FindAllByUserId(db *gorm.DB, userId string, publicOnly bool) ([]entity.ReadingList, error) {
+	var lists []entity.ReadingList
+	query := db.Where("user_id = ?", userId)
+	if publicOnly {
+		query = query.Where("visibility = ?", entity.ReadingListVisibilityPublic)
+	}
+	err := query.Order("created_at asc").Find(&lists).Error
+	return lists, err
+}
+
+func (r *ReadingListRepository) FindByIdForUpdate(db *gorm.DB, list *entity.ReadingList, id uint) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Take(list).Error
+}
+
+// CountPostsByListIds counts the visible posts of each list, soft deleted posts are left out.
+func (r *ReadingListRepository) CountPostsByListIds(db *gorm.DB, listIds []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64)
+	if len(listIds) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ReadingListID uint
+		Total         int64
+	}
+	err := db.Model(&entity.ReadingListItem{}).
+		Select("reading_list_items.reading_list_id, COUNT(*) AS total").
+		Joins("inner join posts p on p.id = reading_list_items.post_id and p.deleted_at IS NULL").
+		Where("reading_list_items.reading_list_id IN ?", listIds).
+		Group("reading_list_items.reading_list_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ReadingListID] = row.Total
+	}
+	return counts, nil
+}
+
+func (r *ReadingListRepository) FindItems(db *gorm.DB, listId uint) ([]entity.ReadingListItem, error) {
+	var items []entity.ReadingListItem
+	err := db.Where("reading_list_id = ?", listId).Order("position asc").Find(&items).Error
+	return items, err
+}
+
+// InsertItem puts the post at position, shifting the following items down, or appends it when position is 0.
+// inserted is false when the post already is in the list.
+func (r *ReadingListRepository) InsertItem(db *gorm.DB, listId uint, postId uint, position int) (bool, error) {
+	var exists int64
+	if err := db.Model(&entity.ReadingListItem{}).Where("reading_list_id = ? AND post_id = ?", listId, postId).Count(&exists).Error; err != nil {
+		return false, err
+	}
+	if exists > 0 {
+		return false, nil
+	}
+
+	var last int
+	if err := db.Model(&entity.ReadingListItem{}).Where("reading_list_id = ?", listId).
+		Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+		return false, err
+	}
+	if position <= 0 || position > last {
+		position = last + 1
+	} else if err := db.Model(&entity.ReadingListItem{}).
+		Where("reading_list_id = ? AND position >= ?", listId, position).
+		UpdateColumn("position", gorm.Expr("position + 1")).Error; err != nil {
+		return false, err
+	}
+
+	return true, db.Create(&entity.ReadingListItem{ReadingListID: listId, PostID: postId, Position: position}).Error
+}
+
+// RemoveItem deletes the post from the list and closes the gap it leaves.
+func (r *ReadingListRepository) RemoveItem(db *gorm.DB, listId uint, postId uint) (bool, error) {
+	item := new(entity.ReadingListItem)
+	if err := db.Where("reading_list_id = ? AND post_id = ?", listId, postId).Take(item).Error; err != nil {
+		return false, nil
+	}
+	if err := db.Delete(item).Error; err != nil {
+		return false, err
+	}
+	return true, db.Model(&entity.ReadingListItem{}).
+		Where("reading_list_id = ? AND position > ?", listId, item.Position).
+		UpdateColumn("position", gorm.Expr("position - 1")).Error
+}
+
+// SetPositions numbers the items in the order of postIds, starting at 1.
+func (r *ReadingListRepository) SetPositions(db *gorm.DB, listId uint, postIds []uint) error {
+	for i, postId := range postIds {
+		err := db.Model(&entity.ReadingListItem{}).
+			Where("reading_list_id = ? AND post_id = ?", listId, postId).
+			UpdateColumn("position", i+1).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReadingListRepository) DeleteWithItems(db *gorm.DB, list *entity.ReadingList) error {
+	if err := db.Where("reading_list_id = ?", list.ID).Delete(&entity.ReadingListItem{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(list).Error
+}
+
+// HardDeleteByUserId removes the lists of a user and takes the user's posts out of everyone else's lists.
+func (r *ReadingListRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	postIds := db.Unscoped().Model(&entity.Post{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Where("post_id IN (?)", postIds).Delete(&entity.ReadingListItem{}).Error; err != nil {
+		return err
+	}
+	listIds := db.Model(&entity.ReadingList{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Where("reading_list_id IN (?)", listIds).Delete(&entity.ReadingListItem{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userId).Delete(&entity.ReadingList{}).Error
+}
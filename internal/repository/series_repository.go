@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type SeriesRepository struct {
+	Repository[entity.Series]
+	Log *logrus.Logger
+}
+
+func NewSeriesRepository(log *logrus.Logger) *SeriesRepository {
+	return &SeriesRepository{
+		Log: log,
+	}
+}
+
+func preloadSeriesUser(db *gorm.DB) *gorm.DB {
+	return db.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("ID", "Name", "Username")
+	})
+}
+
+func (r *SeriesRepository) FindBySlug(db *gorm.DB, series *entity.Series, slug string) error {
+	return db.Scopes(preloadSeriesUser).Where("slug = ?", slug).Take(series).Error
+}
+
+func (r *SeriesRepository) ExistsBySlug(db *gorm.DB, slug string) (bool, error) {
+	var total int64
+	err := db.Model(&entity.Series{}).Where("slug = ?", slug).Count(&total).Error
+	return total > 0, err
+}
+
+func (r *SeriesRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.Series, error) {
+	var series []entity.Series
+	err := db.Scopes(preloadSeriesUser).Where("user_id = ?", userId).Order("created_at asc").Find(&series).Error
+	return series, err
+}
+
+func (r *SeriesRepository) FindByIdForUpdate(db *gorm.DB, series *entity.Series, id uint) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(preloadSeriesUser).Where("id = ?", id).Take(series).Error
+}
+
+// FindByPostId returns the series the post is a part of.
+func (r *SeriesRepository) FindByPostId(db *gorm.DB, series *entity.Series, postId uint) error {
+	return db.
+		Joins("inner join series_posts sp on sp.series_id = series.id").
+		Where("sp.post_id = ?", postId).
+		Take(series).Error
+}
+
+// CountPostsBySeriesIds counts the parts of each series, publishedOnly leaves out the ones readers cannot see yet.
+func (r *SeriesRepository) CountPostsBySeriesIds(db *gorm.DB, seriesIds []uint, publishedOnly bool) (map[uint]int64, error) {
+	counts := make(map[uint]int64)
+	if len(seriesIds) == 0 {
+		return counts, nil
+	}
+
+	query := db.Model(&entity.SeriesPost{}).
+		Select("series_posts.series_id, COUNT(*) AS total").
+		Joins("inner join posts p on p.id = series_posts.post_id and p.deleted_at IS NULL").
+		Where("series_posts.series_id IN ?", seriesIds)
+	if publishedOnly {
+		query = query.Where("p.published_at IS NOT NULL AND p.published_at <= ?", time.Now())
+	}
+
+	var rows []struct {
+		SeriesID uint
+		Total    int64
+	}
+	if err := query.Group("series_posts.series_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.SeriesID] = row.Total
+	}
+	return counts, nil
+}
+
+func (r *SeriesRepository) FindItems(db *gorm.DB, seriesId uint) ([]entity.SeriesPost, error) {
+	var items []entity.SeriesPost
+	err := db.Where("series_id = ?", seriesId).Order("position asc").Find(&items).Error
+	return items, err
+}
+
+// InsertItem puts the post at position, shifting the following parts down, or appends it when position is 0.
+func (r *SeriesRepository) InsertItem(db *gorm.DB, seriesId uint, postId uint, position int) error {
+	var last int
+	if err := db.Model(&entity.SeriesPost{}).Where("series_id = ?", seriesId).
+		Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+		return err
+	}
+	if position <= 0 || position > last {
+		position = last + 1
+	} else if err := db.Model(&entity.SeriesPost{}).
+		Where("series_id = ? AND position >= ?", seriesId, position).
+		UpdateColumn("position", gorm.Expr("position + 1")).Error; err != nil {
+		return err
+	}
+
+	return db.Create(&entity.SeriesPost{SeriesID: seriesId, PostID: postId, Position: position}).Error
+}
+
+// RemoveItem deletes the post from the series and closes the gap it leaves.
+func (r *SeriesRepository) RemoveItem(db *gorm.DB, seriesId uint, postId uint) (bool, error) {
+	item := new(entity.SeriesPost)
+	if err := db.Where("series_id = ? AND post_id = ?", seriesId, postId).Take(item).Error; err != nil {
+		return false, nil
+	}
+	if err := db.Delete(item).Error; err != nil {
+		return false, err
+	}
+	return true, db.Model(&entity.SeriesPost{}).
+		Where("series_id = ? AND position > ?", seriesId, item.Position).
+		UpdateColumn("position", gorm.Expr("position - 1")).Error
+}
+
+// SetPositions numbers the parts in the order of postIds, starting at 1.
+func (r *SeriesRepository) SetPositions(db *gorm.DB, seriesId uint, postIds []uint) error {
+	for i, postId := range postIds {
+		err := db.Model(&entity.SeriesPost{}).
+			Where("series_id = ? AND post_id = ?", seriesId, postId).
+			UpdateColumn("position", i+1).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *SeriesRepository) DeleteWithItems(db *gorm.DB, series *entity.Series) error {
+	if err := db.Where("series_id = ?", series.ID).Delete(&entity.SeriesPost{}).Error; err != nil {
+		return err
+	}
+	return db.Delete(series).Error
+}
+
+// TransferByUserId hands the series of a user over with the posts they hold.
+func (r *SeriesRepository) TransferByUserId(db *gorm.DB, fromUserId string, toUserId string) error {
+	return db.Model(&entity.Series{}).Where("user_id = ?", fromUserId).Update("user_id", toUserId).Error
+}
+
+// HardDeleteByUserId removes the series of a user and takes the user's posts out of any series.
+func (r *SeriesRepository) HardDeleteByUserId(db *gorm.DB, userId string) error {
+	postIds := db.Unscoped().Model(&entity.Post{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Where("post_id IN (?)", postIds).Delete(&entity.SeriesPost{}).Error; err != nil {
+		return err
+	}
+	seriesIds := db.Model(&entity.Series{}).Select("id").Where("user_id = ?", userId)
+	if err := db.Where("series_id IN (?)", seriesIds).Delete(&entity.SeriesPost{}).Error; err != nil {
+		return err
+	}
+	return db.Where("user_id = ?", userId).Delete(&entity.Series{}).Error
+}
+
+// FindSitemapEntries returns the series with published parts, dated by their latest change.
+func (r *SeriesRepository) FindSitemapEntries(db *gorm.DB) ([]model.SitemapEntry, error) {
+	var entries []model.SitemapEntry
+	err := db.Table("series s").
+		Select("s.slug AS name, GREATEST(s.updated_at, MAX(p.updated_at)) AS last_mod").
+		Joins("inner join series_posts sp on sp.series_id = s.id").
+		Joins("inner join posts p on p.id = sp.post_id").
+		Where("p.deleted_at IS NULL AND p.published_at IS NOT NULL AND p.published_at <= ?", time.Now()).
+		Group("s.id, s.slug, s.updated_at").
+		Order("s.id asc").
+		Scan(&entries).Error
+	return entries, err
+}
@@ -3,7 +3,9 @@ package repository
 import (
 	"github.com/sirupsen/logrus"
 	"go-blog/internal/entity"
+	"go-blog/internal/model"
 	"gorm.io/gorm"
+	"time"
 )
 
 type TagRepository struct {
@@ -20,3 +22,21 @@ func NewTagRepository(log *logrus.Logger) *TagRepository {
 func (r *TagRepository) FindByName(db *gorm.DB, tag *entity.Tag, name string) error {
 	return db.Take(tag, "name = ?", name).Error
 }
+
+func (r *TagRepository) FindBySlug(db *gorm.DB, tag *entity.Tag, slug string) error {
+	return db.Take(tag, "slug = ?", slug).Error
+}
+
+// FindSitemapEntries returns the tags with published posts, dated by their latest post update.
+func (r *TagRepository) FindSitemapEntries(db *gorm.DB) ([]model.SitemapEntry, error) {
+	var entries []model.SitemapEntry
+	err := db.Table("tags t").
+		Select("t.slug AS name, MAX(p.updated_at) AS last_mod").
+		Joins("inner join post_tags pt on pt.tag_id = t.id").
+		Joins("inner join posts p on p.id = pt.post_id").
+		Where("p.deleted_at IS NULL AND p.published_at IS NOT NULL AND p.published_at <= ?", time.Now()).
+		Group("t.id, t.slug").
+		Order("t.id asc").
+		Scan(&entries).Error
+	return entries, err
+}
@@ -3,7 +3,10 @@ package repository
 import (
 	"github.com/sirupsen/logrus"
 	"go-blog/internal/entity"
+	"go-blog/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type UserRepository struct {
@@ -23,3 +26,48 @@ func (r *Repository[T]) FindByEmail(tx *gorm.DB, user *entity.User, email string
 func (r *Repository[T]) FindByUsername(tx *gorm.DB, user *entity.User, username string) error {
 	return tx.Where("username = ?", username).Take(user).Error
 }
+
+func (r *UserRepository) FindDeletedById(tx *gorm.DB, user *entity.User, id string) error {
+	return tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Take(user).Error
+}
+
+func (r *UserRepository) FindDeletedByEmail(tx *gorm.DB, user *entity.User, email string) error {
+	return tx.Unscoped().Where("email = ? AND deleted_at IS NOT NULL", email).Take(user).Error
+}
+
+func (r *UserRepository) Restore(tx *gorm.DB, id string) error {
+	return tx.Unscoped().Model(&entity.User{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// FirstOrCreatePlaceholder loads the placeholder user into user, which must be empty so only the flag
+// is looked up. When missing it is created from attrs.
+func (r *UserRepository) FirstOrCreatePlaceholder(tx *gorm.DB, user *entity.User, attrs *entity.User) error {
+	attrs.IsPlaceholder = true
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("is_placeholder = ?", true).
+		Attrs(attrs).
+		FirstOrCreate(user).Error
+}
+
+// UpdateFields updates the given columns only, unlike Updates it also writes empty values.
+func (r *UserRepository) UpdateFields(db *gorm.DB, id string, fields map[string]any) error {
+	return db.Model(&entity.User{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *UserRepository) HardDelete(tx *gorm.DB, id string) error {
+	return tx.Unscoped().Where("id = ?", id).Delete(&entity.User{}).Error
+}
+
+// FindSitemapEntries returns the authors with published posts, dated by their latest post update.
+func (r *UserRepository) FindSitemapEntries(tx *gorm.DB) ([]model.SitemapEntry, error) {
+	var entries []model.SitemapEntry
+	err := tx.Table("users u").
+		Select("u.username AS name, MAX(p.updated_at) AS last_mod").
+		Joins("inner join posts p on p.user_id = u.id").
+		Where("u.deleted_at IS NULL AND p.deleted_at IS NULL").
+		Where("p.published_at IS NOT NULL AND p.published_at <= ?", time.Now()).
+		Group("u.id, u.username").
+		Order("u.created_at asc").
+		Scan(&entries).Error
+	return entries, err
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"gorm.io/gorm"
+	"time"
+)
+
+type UsernameHistoryRepository struct {
+	Repository[entity.UsernameHistory]
+	Log *logrus.Logger
+}
+
+func NewUsernameHistoryRepository(log *logrus.Logger) *UsernameHistoryRepository {
+	return &UsernameHistoryRepository{
+		Log: log,
+	}
+}
+
+// FindLatestByOldUsername returns the most recent rename away from username made after since, with its user preloaded.
+func (r *UsernameHistoryRepository) FindLatestByOldUsername(db *gorm.DB, history *entity.UsernameHistory, username string, since time.Time) error {
+	return db.
+		Joins("User").
+		Where("username_histories.old_username = ? AND username_histories.created_at > ?", username, since).
+		Order("username_histories.id desc").
+		Take(history).Error
+}
+
+func (r *UsernameHistoryRepository) DeleteByUserIdAndOldUsername(db *gorm.DB, userId string, username string) error {
+	return db.Where("user_id = ? AND old_username = ?", userId, username).Delete(&entity.UsernameHistory{}).Error
+}
+
+func (r *UsernameHistoryRepository) FindAllByUserId(db *gorm.DB, userId string) ([]entity.UsernameHistory, error) {
+	var histories []entity.UsernameHistory
+	err := db.Where("user_id = ?", userId).Order("id asc").Find(&histories).Error
+	return histories, err
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+)
+
+type BookmarkUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	Validate           *validator.Validate
+	BookmarkRepository *repository.BookmarkRepository
+	PostRepository     *repository.PostRepository
+}
+
+func NewBookmarkUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, bookmarkRepository *repository.BookmarkRepository, postRepository *repository.PostRepository,
+) *BookmarkUseCase {
+	return &BookmarkUseCase{
+		DB:                 db,
+		Log:                logger,
+		Validate:           validate,
+		BookmarkRepository: bookmarkRepository,
+		PostRepository:     postRepository,
+	}
+}
+
+func (c *BookmarkUseCase) Add(ctx context.Context, request *model.BookmarkRequest) (*model.BookmarkResponse, error) {
+	return c.apply(ctx, request, true)
+}
+
+func (c *BookmarkUseCase) Remove(ctx context.Context, request *model.BookmarkRequest) (*model.BookmarkResponse, error) {
+	return c.apply(ctx, request, false)
+}
+
+func (c *BookmarkUseCase) apply(ctx context.Context, request *model.BookmarkRequest, bookmarked bool) (*model.BookmarkResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	post := new(entity.Post)
+	if err := tx.Where("slug = ?", request.Slug).Take(post).Error; err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	var err error
+	if bookmarked {
+		err = c.BookmarkRepository.Add(tx, &entity.Bookmark{UserID: request.UserId, PostID: post.ID})
+	} else {
+		err = c.BookmarkRepository.Remove(tx, request.UserId, post.ID)
+	}
+	if err != nil {
+		c.Log.Warnf("Failed to update bookmark : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.BookmarkResponse{Slug: post.Slug, Bookmarked: bookmarked}, nil
+}
@@ -0,0 +1,500 @@
+package usecase
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/gateway/realtime"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type CommentUseCase struct {
+	DB                *gorm.DB
+	Log               *logrus.Logger
+	Validate          *validator.Validate
+	Config            *viper.Viper
+	CommentRepository *repository.CommentRepository
+	PostRepository    *repository.PostRepository
+	UserRepository    *repository.UserRepository
+	SpamFilter        *helper.SpamFilter
+	Publisher         realtime.Publisher
+}
+
+func NewCommentUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, commentRepository *repository.CommentRepository, postRepository *repository.PostRepository, userRepository *repository.UserRepository, spamFilter *helper.SpamFilter, publisher realtime.Publisher,
+) *CommentUseCase {
+	return &CommentUseCase{
+		DB:                db,
+		Log:               logger,
+		Validate:          validate,
+		Config:            config,
+		CommentRepository: commentRepository,
+		PostRepository:    postRepository,
+		UserRepository:    userRepository,
+		SpamFilter:        spamFilter,
+		Publisher:         publisher,
+	}
+}
+
+// List returns the comments of a post either as a flat page, or as a page of top level
+// comments with their whole reply tree (the default).
+func (c *CommentUseCase) List(ctx context.Context, request *model.SearchCommentRequest) ([]*model.CommentResponse, int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, 0, fiber.ErrBadRequest
+	}
+
+	post := new(entity.Post)
+	if err := c.PostRepository.FindBySlug(tx, post, request.Slug); err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, 0, fiber.ErrNotFound
+	}
+
+	var response []*model.CommentResponse
+	var total int64
+	if request.Mode == "flat" {
+		comments, count, err := c.CommentRepository.FindFlat(tx, post.ID, request.Paginate)
+		if err != nil {
+			c.Log.Warnf("Failed to get comments : %+v", err)
+			return nil, 0, fiber.ErrInternalServerError
+		}
+		response = make([]*model.CommentResponse, len(comments))
+		for i, comment := range comments {
+			response[i] = converter.CommentToResponse(&comment)
+		}
+		total = count
+	} else {
+		roots, count, err := c.CommentRepository.FindRoots(tx, post.ID, request.Paginate)
+		if err != nil {
+			c.Log.Warnf("Failed to get comments : %+v", err)
+			return nil, 0, fiber.ErrInternalServerError
+		}
+		rootIds := make([]uint, len(roots))
+		for i, root := range roots {
+			rootIds[i] = root.ID
+		}
+		replies, err := c.CommentRepository.FindByRootIds(tx, rootIds)
+		if err != nil {
+			c.Log.Warnf("Failed to get comment replies : %+v", err)
+			return nil, 0, fiber.ErrInternalServerError
+		}
+		response = buildCommentTree(roots, replies)
+		total = count
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	return response, total, nil
+}
+
+func (c *CommentUseCase) Create(ctx context.Context, request *model.CreateCommentRequest) (*model.CommentResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	request.Content = strings.TrimSpace(request.Content)
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	post := new(entity.Post)
+	if err := c.PostRepository.FindBySlug(tx, post, request.Slug); err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	comment := &entity.Comment{
+		PostID:  post.ID,
+		UserID:  request.UserID,
+		Content: request.Content,
+	}
+
+	if request.ParentID != nil {
+		parent := new(entity.Comment)
+		if err := c.CommentRepository.FindById(tx, parent, *request.ParentID); err != nil || parent.PostID != post.ID {
+			c.Log.Warnf("Failed to find parent comment %d : %+v", *request.ParentID, err)
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid parent comment")
+		}
+		rootId := parent.ID
+		if parent.RootID != nil {
+			rootId = *parent.RootID
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootId
+	}
+
+	signals := &helper.SpamSignals{
+		Content:  request.Content,
+		Honeypot: request.Website,
+	}
+	if request.RenderedAt > 0 {
+		signals.SinceRender = time.Since(time.UnixMilli(request.RenderedAt))
+	}
+	status, err := c.screen(tx, comment, signals, post.UserID == request.UserID)
+	if err != nil {
+		c.Log.Warnf("Failed to screen comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	comment.Status = status
+
+	if err := c.CommentRepository.Create(tx, comment); err != nil {
+		c.Log.Warnf("Failed create comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if comment.Status == entity.CommentStatusApproved {
+		if err := c.PostRepository.IncrementCommentCount(tx, post.ID, 1); err != nil {
+			c.Log.Warnf("Failed update comment count : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.CommentRepository.FindByIdWithUser(tx, comment, comment.ID); err != nil {
+		c.Log.Warnf("Failed find comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if comment.Status == entity.CommentStatusApproved {
+		publishPostUpdated(ctx, c.DB, c.Publisher, c.Log, comment.PostID, "comments")
+	}
+
+	return converter.CommentToAuthorResponse(comment), nil
+}
+
+// Update lets authors edit their comment during COMMENT_EDIT_WINDOW minutes after posting it.
+func (c *CommentUseCase) Update(ctx context.Context, request *model.UpdateCommentRequest) (*model.CommentResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	request.Content = strings.TrimSpace(request.Content)
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	comment := new(entity.Comment)
+	if err := c.CommentRepository.FindById(tx, comment, request.ID); err != nil {
+		c.Log.Warnf("Failed find comment : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if comment.UserID != request.UserID {
+		return nil, fiber.ErrForbidden
+	}
+
+	if comment.CreatedAt != nil && time.Since(*comment.CreatedAt) > c.editWindow() {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Comment can no longer be edited")
+	}
+
+	// An edit is screened again, it can only move an approved comment back to the queue.
+	post := new(entity.Post)
+	if err := c.PostRepository.FindById(tx, post, comment.PostID); err != nil {
+		c.Log.Warnf("Failed find post : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+	previousStatus := comment.Status
+	comment.Content = request.Content
+	status, err := c.screen(tx, comment, &helper.SpamSignals{Content: request.Content}, post.UserID == request.UserID)
+	if err != nil {
+		c.Log.Warnf("Failed to screen comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if previousStatus != entity.CommentStatusApproved {
+		status = previousStatus
+	}
+
+	now := time.Now()
+	comment.EditedAt = &now
+	comment.Status = status
+	if err := c.CommentRepository.Save(tx, comment); err != nil {
+		c.Log.Warnf("Failed update comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.PostRepository.IncrementCommentCount(tx, comment.PostID, countDelta(previousStatus, status)); err != nil {
+		c.Log.Warnf("Failed update comment count : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.CommentRepository.FindByIdWithUser(tx, comment, comment.ID); err != nil {
+		c.Log.Warnf("Failed find comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if previousStatus == entity.CommentStatusApproved || comment.Status == entity.CommentStatusApproved {
+		publishPostUpdated(ctx, c.DB, c.Publisher, c.Log, comment.PostID, "comments")
+	}
+
+	return converter.CommentToAuthorResponse(comment), nil
+}
+
+// Delete soft deletes a comment, replies stay and the comment shows as deleted in the tree.
+func (c *CommentUseCase) Delete(ctx context.Context, userId string, id uint) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	comment := new(entity.Comment)
+	if err := c.CommentRepository.FindById(tx, comment, id); err != nil {
+		c.Log.Warnf("Failed find comment : %+v", err)
+		return fiber.ErrNotFound
+	}
+
+	if comment.UserID != userId {
+		return fiber.ErrForbidden
+	}
+
+	if err := c.CommentRepository.Delete(tx, comment); err != nil {
+		c.Log.Warnf("Failed delete comment : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if comment.Status == entity.CommentStatusApproved {
+		if err := c.PostRepository.IncrementCommentCount(tx, comment.PostID, -1); err != nil {
+			c.Log.Warnf("Failed update comment count : %+v", err)
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if comment.Status == entity.CommentStatusApproved {
+		publishPostUpdated(ctx, c.DB, c.Publisher, c.Log, comment.PostID, "comments")
+	}
+
+	return nil
+}
+
+// ListModeration returns the comments in a moderation status, only on their own posts for authors.
+func (c *CommentUseCase) ListModeration(ctx context.Context, request *model.SearchModerationRequest) ([]*model.CommentResponse, int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, 0, fiber.ErrBadRequest
+	}
+
+	comments, total, err := c.CommentRepository.FindForModeration(tx, request)
+	if err != nil {
+		c.Log.Warnf("Failed to get comments for moderation : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	response := make([]*model.CommentResponse, len(comments))
+	for i, comment := range comments {
+		response[i] = converter.CommentToModerationResponse(&comment)
+	}
+	return response, total, nil
+}
+
+func (c *CommentUseCase) Moderate(ctx context.Context, request *model.ModerateCommentRequest) (*model.CommentResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	comment := new(entity.Comment)
+	if err := c.CommentRepository.FindById(tx, comment, request.ID); err != nil {
+		c.Log.Warnf("Failed find comment : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if request.ModeratorID != "" {
+		post := new(entity.Post)
+		if err := c.PostRepository.FindById(tx, post, comment.PostID); err != nil || post.UserID != request.ModeratorID {
+			return nil, fiber.ErrForbidden
+		}
+	}
+
+	previousStatus := comment.Status
+	now := time.Now()
+	comment.Status = request.Status
+	comment.ModeratedBy = request.ModeratorID
+	comment.ModeratedAt = &now
+	if err := c.CommentRepository.Save(tx, comment); err != nil {
+		c.Log.Warnf("Failed moderate comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.PostRepository.IncrementCommentCount(tx, comment.PostID, countDelta(previousStatus, comment.Status)); err != nil {
+		c.Log.Warnf("Failed update comment count : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.CommentRepository.FindByIdWithUser(tx, comment, comment.ID); err != nil {
+		c.Log.Warnf("Failed find comment : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if previousStatus == entity.CommentStatusApproved || comment.Status == entity.CommentStatusApproved {
+		publishPostUpdated(ctx, c.DB, c.Publisher, c.Log, comment.PostID, "comments")
+	}
+
+	return converter.CommentToModerationResponse(comment), nil
+}
+
+// screen scores the comment and picks its initial status: spam over COMMENT_SPAM_THRESHOLD,
+// pending when suspicious or when the commenter is new, approved otherwise.
+// Post authors commenting on their own post are always approved.
+func (c *CommentUseCase) screen(tx *gorm.DB, comment *entity.Comment, signals *helper.SpamSignals, postAuthor bool) (string, error) {
+	// Repeats and posting rate only matter for new comments.
+	if comment.ID == 0 {
+		duplicate, err := c.CommentRepository.ExistsDuplicate(tx, comment.UserID, signals.Content, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return "", err
+		}
+		signals.Duplicate = duplicate
+
+		last := new(entity.Comment)
+		if err := c.CommentRepository.FindLatestByUserId(tx, last, comment.UserID); err == nil && last.CreatedAt != nil {
+			signals.SinceLastComment = time.Since(*last.CreatedAt)
+		}
+	}
+
+	score, reasons := c.SpamFilter.Score(signals)
+	comment.SpamScore = score
+	comment.SpamNotes = strings.Join(reasons, ",")
+
+	if postAuthor {
+		return entity.CommentStatusApproved, nil
+	}
+	if score >= c.configInt("COMMENT_SPAM_THRESHOLD", 80) {
+		return entity.CommentStatusSpam, nil
+	}
+	if score >= c.configInt("COMMENT_PENDING_THRESHOLD", 30) {
+		return entity.CommentStatusPending, nil
+	}
+
+	trusted, err := c.isTrusted(tx, comment.UserID)
+	if err != nil {
+		return "", err
+	}
+	if !trusted {
+		return entity.CommentStatusPending, nil
+	}
+	return entity.CommentStatusApproved, nil
+}
+
+// isTrusted tells if a commenter skips the queue: an account older than COMMENT_TRUSTED_DAYS
+// or with at least one approved comment.
+func (c *CommentUseCase) isTrusted(tx *gorm.DB, userId string) (bool, error) {
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, userId); err != nil {
+		return false, err
+	}
+	if time.Since(user.CreatedAt) > time.Hour*24*time.Duration(c.configInt("COMMENT_TRUSTED_DAYS", 7)) {
+		return true, nil
+	}
+
+	approved, err := c.CommentRepository.CountApprovedByUserId(tx, userId)
+	if err != nil {
+		return false, err
+	}
+	return approved > 0, nil
+}
+
+func (c *CommentUseCase) configInt(key string, fallback int) int {
+	if value := c.Config.GetInt(key); value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// countDelta is the change of the post comment count when a comment goes from one status to another.
+func countDelta(from string, to string) int {
+	switch {
+	case from != entity.CommentStatusApproved && to == entity.CommentStatusApproved:
+		return 1
+	case from == entity.CommentStatusApproved && to != entity.CommentStatusApproved:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func (c *CommentUseCase) editWindow() time.Duration {
+	if minutes := c.Config.GetInt("COMMENT_EDIT_WINDOW"); minutes > 0 {
+		return time.Minute * time.Duration(minutes)
+	}
+	return 15 * time.Minute
+}
+
+// buildCommentTree nests the replies under their roots and drops deleted comments without visible replies.
+func buildCommentTree(roots []entity.Comment, replies []entity.Comment) []*model.CommentResponse {
+	nodes := make(map[uint]*model.CommentResponse, len(roots)+len(replies))
+	for _, comment := range append(append([]entity.Comment{}, roots...), replies...) {
+		nodes[comment.ID] = converter.CommentToResponse(&comment)
+	}
+
+	for _, reply := range replies {
+		if reply.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*reply.ParentID]; ok {
+			parent.Replies = append(parent.Replies, nodes[reply.ID])
+		}
+	}
+
+	tree := make([]*model.CommentResponse, 0, len(roots))
+	for _, root := range roots {
+		if node := pruneDeletedComments(nodes[root.ID]); node != nil {
+			tree = append(tree, node)
+		}
+	}
+	return tree
+}
+
+func pruneDeletedComments(node *model.CommentResponse) *model.CommentResponse {
+	replies := node.Replies[:0]
+	for _, reply := range node.Replies {
+		if reply = pruneDeletedComments(reply); reply != nil {
+			replies = append(replies, reply)
+		}
+	}
+	node.Replies = replies
+	if node.Deleted && len(node.Replies) == 0 {
+		return nil
+	}
+	return node
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// encodeFeedCursor points after the given post, posts are ordered by publish time then id.
+func encodeFeedCursor(publishedAt time.Time, id uint) string {
+	raw := strconv.FormatInt(publishedAt.UnixNano(), 10) + ":" + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, 0, errors.New("malformed cursor")
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	postId, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	return time.Unix(0, unixNano), uint(postId), nil
+}
@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ExportUseCase struct {
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	Config                    *viper.Viper
+	UserRepository            *repository.UserRepository
+	PostRepository            *repository.PostRepository
+	UsernameHistoryRepository *repository.UsernameHistoryRepository
+	EmailChangeRepository     *repository.EmailChangeRepository
+	DataExportRepository      *repository.DataExportRepository
+}
+
+func NewExportUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, userRepository *repository.UserRepository, postRepository *repository.PostRepository, usernameHistoryRepository *repository.UsernameHistoryRepository, emailChangeRepository *repository.EmailChangeRepository, dataExportRepository *repository.DataExportRepository,
+) *ExportUseCase {
+	return &ExportUseCase{
+		DB:                        db,
+		Log:                       logger,
+		Validate:                  validate,
+		Config:                    config,
+		UserRepository:            userRepository,
+		PostRepository:            postRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+		EmailChangeRepository:     emailChangeRepository,
+		DataExportRepository:      dataExportRepository,
+	}
+}
+
+// Create queues a new export of the user data and builds the archive in the background,
+// an export that is still pending is returned instead of starting another one.
+func (c *ExportUseCase) Create(ctx context.Context, userId string) (*model.DataExportResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.UserRepository.FindById(tx, new(entity.User), userId); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	export := new(entity.DataExport)
+	if err := c.DataExportRepository.FindPendingByUserId(tx, export, userId); err == nil {
+		return converter.DataExportToResponse(export, ""), nil
+	}
+
+	export = &entity.DataExport{
+		ID:        uuid.New().String(),
+		UserID:    userId,
+		Status:    entity.DataExportStatusPending,
+		ExpiresAt: time.Now().Add(c.ttl()),
+	}
+	if err := c.DataExportRepository.Create(tx, export); err != nil {
+		c.Log.Warnf("Failed create data export : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	go c.build(export.ID)
+
+	return converter.DataExportToResponse(export, ""), nil
+}
+
+func (c *ExportUseCase) Get(ctx context.Context, userId string, id string) (*model.DataExportResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Var(id, "uuid4"); err != nil {
+		c.Log.Warnf("Invalid export id : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid export id")
+	}
+
+	export := new(entity.DataExport)
+	if err := c.DataExportRepository.FindByIdAndUserId(tx, export, id, userId); err != nil {
+		c.Log.Warnf("Failed find data export : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	var downloadURL string
+	if export.Status == entity.DataExportStatusReady {
+		downloadURL = c.downloadURL(export)
+	}
+	return converter.DataExportToResponse(export, downloadURL), nil
+}
+
+// Download checks the signed link and returns the archive path with the file name to serve it as.
+func (c *ExportUseCase) Download(ctx context.Context, request *model.DownloadDataExportRequest) (string, string, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid download request : %+v", err)
+		return "", "", fiber.ErrBadRequest
+	}
+
+	expected := c.sign(request.ID, request.Expires)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(request.Signature))) {
+		return "", "", fiber.ErrForbidden
+	}
+	if time.Now().Unix() > request.Expires {
+		return "", "", fiber.NewError(fiber.StatusGone, "Download link expired")
+	}
+
+	export := new(entity.DataExport)
+	if err := c.DataExportRepository.FindById(tx, export, request.ID); err != nil || export.Status != entity.DataExportStatusReady {
+		c.Log.Warnf("Failed find ready data export : %+v", err)
+		return "", "", fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return "", "", fiber.ErrInternalServerError
+	}
+
+	return export.FilePath, fmt.Sprintf("go-blog-export-%s.zip", export.CreatedAt.Format("20060102")), nil
+}
+
+// Cleanup removes expired archives and fails exports whose build never finished.
+func (c *ExportUseCase) Cleanup(ctx context.Context) error {
+	db := c.DB.WithContext(ctx)
+	now := time.Now()
+
+	stale, err := c.DataExportRepository.FindStalePending(db, now.Add(-time.Hour))
+	if err != nil {
+		c.Log.Warnf("Failed to find stale data exports : %+v", err)
+		return err
+	}
+	for _, export := range stale {
+		export.Status = entity.DataExportStatusFailed
+		if err := c.DataExportRepository.Save(db, &export); err != nil {
+			c.Log.Warnf("Failed to mark data export '%s' failed : %+v", export.ID, err)
+		}
+	}
+
+	expired, err := c.DataExportRepository.FindExpired(db, now)
+	if err != nil {
+		c.Log.Warnf("Failed to find expired data exports : %+v", err)
+		return err
+	}
+	for _, export := range expired {
+		if export.FilePath != "" {
+			if err := os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+				c.Log.Warnf("Failed to remove data export file '%s' : %+v", export.FilePath, err)
+				continue
+			}
+		}
+		if err := c.DataExportRepository.Delete(db, &export); err != nil {
+			c.Log.Warnf("Failed to delete data export '%s' : %+v", export.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *ExportUseCase) build(id string) {
+	db := c.DB.WithContext(context.Background())
+
+	export := new(entity.DataExport)
+	if err := c.DataExportRepository.FindById(db, export, id); err != nil {
+		c.Log.Warnf("Failed find data export '%s' : %+v", id, err)
+		return
+	}
+
+	path, size, err := c.writeArchive(db, export)
+	if err != nil {
+		c.Log.Warnf("Failed to build data export '%s' : %+v", id, err)
+		export.Status = entity.DataExportStatusFailed
+	} else {
+		now := time.Now()
+		export.Status = entity.DataExportStatusReady
+		export.FilePath = path
+		export.Size = size
+		export.CompletedAt = &now
+	}
+
+	if err := c.DataExportRepository.Save(db, export); err != nil {
+		c.Log.Warnf("Failed save data export '%s' : %+v", id, err)
+	}
+}
+
+func (c *ExportUseCase) writeArchive(db *gorm.DB, export *entity.DataExport) (string, int64, error) {
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(db, user, export.UserID); err != nil {
+		return "", 0, err
+	}
+
+	posts, err := c.PostRepository.FindAllByUserId(db, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	histories, err := c.UsernameHistoryRepository.FindAllByUserId(db, user.ID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	account := &model.AccountExport{
+		ID:                user.ID,
+		Email:             user.Email,
+		Username:          user.Username,
+		PreviousUsernames: make([]model.PreviousUsernameExport, len(histories)),
+		PostCount:         len(posts),
+		CreatedAt:         user.CreatedAt,
+		UpdatedAt:         user.UpdatedAt,
+		ExportedAt:        time.Now(),
+	}
+	for i, history := range histories {
+		account.PreviousUsernames[i] = model.PreviousUsernameExport{Username: history.OldUsername, ChangedAt: history.CreatedAt}
+	}
+	emailChange := new(entity.EmailChange)
+	if err := c.EmailChangeRepository.FindPendingByUserId(db, emailChange, user.ID); err == nil {
+		account.PendingEmail = emailChange.NewEmail
+	}
+
+	dir := c.dir()
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(dir, export.ID+".zip")
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	if err := writeZipJSON(archive, "profile.json", converter.UserToResponse(user)); err != nil {
+		return "", 0, err
+	}
+	if err := writeZipJSON(archive, "account.json", account); err != nil {
+		return "", 0, err
+	}
+
+	names := make(map[string]int)
+	for _, post := range posts {
+		name := post.Slug
+		if name == "" {
+			name = strconv.Itoa(int(post.ID))
+		}
+		// Several posts can share a slug, the later ones get a numeric suffix.
+		if names[name]++; names[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, names[name])
+		}
+
+		w, err := archive.Create("posts/" + name + ".md")
+		if err != nil {
+			return "", 0, err
+		}
+		if _, err := io.WriteString(w, postToMarkdown(&post)); err != nil {
+			return "", 0, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+	return path, info.Size(), nil
+}
+
+func (c *ExportUseCase) downloadURL(export *entity.DataExport) string {
+	expires := export.ExpiresAt.Unix()
+	return fmt.Sprintf("%s/exports/%s/download?expires=%d&signature=%s",
+		strings.TrimRight(c.Config.GetString("APP_BASE_URL"), "/"), export.ID, expires, c.sign(export.ID, expires))
+}
+
+func (c *ExportUseCase) sign(id string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(c.Config.GetString("JWT_SECRET")))
+	mac.Write([]byte(fmt.Sprintf("export:%s:%d", id, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *ExportUseCase) dir() string {
+	if dir := c.Config.GetString("EXPORT_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join("storage", "exports")
+}
+
+func (c *ExportUseCase) ttl() time.Duration {
+	if hours := c.Config.GetInt("EXPORT_TTL_HOURS"); hours > 0 {
+		return time.Hour * time.Duration(hours)
+	}
+	return 24 * time.Hour
+}
+
+func writeZipJSON(archive *zip.Writer, name string, value any) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// postToMarkdown renders a post as Markdown with a YAML front matter.
+func postToMarkdown(post *entity.Post) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.WriteString("title: " + strconv.Quote(post.Title) + "\n")
+	b.WriteString("slug: " + strconv.Quote(post.Slug) + "\n")
+	b.WriteString("tags:")
+	if len(post.Tags) == 0 {
+		b.WriteString(" []")
+	}
+	b.WriteString("\n")
+	for _, tag := range post.Tags {
+		b.WriteString("  - " + strconv.Quote(tag.Name) + "\n")
+	}
+	for _, date := range []struct {
+		key   string
+		value *time.Time
+	}{{"published_at", post.PublishedAt}, {"created_at", post.CreatedAt}, {"updated_at", post.UpdatedAt}} {
+		if date.value != nil {
+			b.WriteString(date.key + ": " + date.value.Format(time.RFC3339) + "\n")
+		}
+	}
+	b.WriteString("---\n\n")
+	b.WriteString(post.Content)
+	if !strings.HasSuffix(post.Content, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+)
+
+type FollowUseCase struct {
+	DB               *gorm.DB
+	Log              *logrus.Logger
+	Validate         *validator.Validate
+	FollowRepository *repository.FollowRepository
+	UserRepository   *repository.UserRepository
+	TagRepository    *repository.TagRepository
+}
+
+func NewFollowUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, followRepository *repository.FollowRepository, userRepository *repository.UserRepository, tagRepository *repository.TagRepository,
+) *FollowUseCase {
+	return &FollowUseCase{
+		DB:               db,
+		Log:              logger,
+		Validate:         validate,
+		FollowRepository: followRepository,
+		UserRepository:   userRepository,
+		TagRepository:    tagRepository,
+	}
+}
+
+// FollowUser is idempotent, following an author twice keeps a single follow.
+func (c *FollowUseCase) FollowUser(ctx context.Context, request *model.FollowUserRequest) (*model.FollowResponse, error) {
+	return c.applyUser(ctx, request, true)
+}
+
+func (c *FollowUseCase) UnfollowUser(ctx context.Context, request *model.FollowUserRequest) (*model.FollowResponse, error) {
+	return c.applyUser(ctx, request, false)
+}
+
+func (c *FollowUseCase) applyUser(ctx context.Context, request *model.FollowUserRequest, follow bool) (*model.FollowResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	followee := new(entity.User)
+	if err := c.UserRepository.FindByUsername(tx, followee, request.Username); err != nil {
+		c.Log.Warnf("Failed find user by username '%s' : %+v", request.Username, err)
+		return nil, fiber.ErrNotFound
+	}
+	if followee.ID == request.UserId {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "You can not follow yourself")
+	}
+
+	var err error
+	if follow {
+		err = c.FollowRepository.FollowUser(tx, request.UserId, followee.ID)
+	} else {
+		err = c.FollowRepository.UnfollowUser(tx, request.UserId, followee.ID)
+	}
+	if err != nil {
+		c.Log.Warnf("Failed to update follow : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.FollowResponse{Following: follow}, nil
+}
+
+func (c *FollowUseCase) FollowTag(ctx context.Context, request *model.FollowTagRequest) (*model.FollowResponse, error) {
+	return c.applyTag(ctx, request, true)
+}
+
+func (c *FollowUseCase) UnfollowTag(ctx context.Context, request *model.FollowTagRequest) (*model.FollowResponse, error) {
+	return c.applyTag(ctx, request, false)
+}
+
+func (c *FollowUseCase) applyTag(ctx context.Context, request *model.FollowTagRequest, follow bool) (*model.FollowResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	tag := new(entity.Tag)
+	if err := c.TagRepository.FindBySlug(tx, tag, request.Slug); err != nil {
+		c.Log.Warnf("Failed find tag by slug '%s' : %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	var err error
+	if follow {
+		err = c.FollowRepository.FollowTag(tx, request.UserId, tag.ID)
+	} else {
+		err = c.FollowRepository.UnfollowTag(tx, request.UserId, tag.ID)
+	}
+	if err != nil {
+		c.Log.Warnf("Failed to update tag follow : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.FollowResponse{Following: follow}, nil
+}
+
+// ListFollowing returns the authors and tags the user follows, most recent first.
+func (c *FollowUseCase) ListFollowing(ctx context.Context, userId string) (*model.FollowingResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	users, err := c.FollowRepository.FindFollowedUsers(tx, userId)
+	if err != nil {
+		c.Log.Warnf("Failed to get followed users : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	tags, err := c.FollowRepository.FindFollowedTags(tx, userId)
+	if err != nil {
+		c.Log.Warnf("Failed to get followed tags : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := &model.FollowingResponse{
+		Users: make([]model.UserOnPost, len(users)),
+		Tags:  make([]*model.TagResponse, len(tags)),
+	}
+	for i, user := range users {
+		response.Users[i] = model.UserOnPost{ID: user.ID, Name: user.Name, Username: user.Username}
+	}
+	for i, tag := range tags {
+		response.Tags[i] = converter.TagToPostResponse(&tag)
+	}
+	return response, nil
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	LoginThrottleScopeAccount = "account"
+	LoginThrottleScopeIP      = "ip"
+)
+
+// LoginLockedError is returned while an account or IP address is locked out,
+// RetryAfter tells the caller how long it has to wait before trying again.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("Too many failed login attempts, try again in %d seconds.", e.RetryAfterSeconds())
+}
+
+func (e *LoginLockedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+type LoginThrottleUseCase struct {
+	DB                      *gorm.DB
+	Log                     *logrus.Logger
+	Validate                *validator.Validate
+	Config                  *viper.Viper
+	LoginThrottleRepository *repository.LoginThrottleRepository
+}
+
+func NewLoginThrottleUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, loginThrottleRepository *repository.LoginThrottleRepository,
+) *LoginThrottleUseCase {
+	return &LoginThrottleUseCase{
+		DB:                      db,
+		Log:                     logger,
+		Validate:                validate,
+		Config:                  config,
+		LoginThrottleRepository: loginThrottleRepository,
+	}
+}
+
+// Check returns a *LoginLockedError when either the account or the IP address is currently locked.
+func (c *LoginThrottleUseCase) Check(ctx context.Context, email string, ip string) error {
+	db := c.DB.WithContext(ctx)
+	now := time.Now()
+
+	var retryAfter time.Duration
+	for scope, identifier := range c.identifiers(email, ip) {
+		throttle := new(entity.LoginThrottle)
+		if err := c.LoginThrottleRepository.FindByIdentifier(db, throttle, scope, identifier); err != nil {
+			continue
+		}
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			if wait := throttle.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// RegisterFailure counts a failed attempt for both the account and the IP address,
+// locking them out with an exponential backoff once the configured limit is reached.
+func (c *LoginThrottleUseCase) RegisterFailure(ctx context.Context, email string, ip string) {
+	for scope, identifier := range c.identifiers(email, ip) {
+		if err := c.registerFailure(ctx, scope, identifier); err != nil {
+			c.Log.Warnf("Failed to register failed login for %s '%s' : %+v", scope, identifier, err)
+		}
+	}
+}
+
+// RegisterSuccess clears the account counter, the IP counter is kept so a single valid
+// account cannot be used to reset the limit while guessing other accounts.
+func (c *LoginThrottleUseCase) RegisterSuccess(ctx context.Context, email string) {
+	identifier := normalizeEmail(email)
+	if err := c.LoginThrottleRepository.DeleteByIdentifier(c.DB.WithContext(ctx), LoginThrottleScopeAccount, identifier); err != nil {
+		c.Log.Warnf("Failed to reset login throttle for '%s' : %+v", identifier, err)
+	}
+}
+
+func (c *LoginThrottleUseCase) Unlock(ctx context.Context, request *model.UnlockLoginRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	if request.Email == "" && request.IP == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Email or IP is required")
+	}
+
+	for scope, identifier := range c.identifiers(request.Email, request.IP) {
+		if err := c.LoginThrottleRepository.DeleteByIdentifier(tx, scope, identifier); err != nil {
+			c.Log.Warnf("Failed to unlock %s '%s' : %+v", scope, identifier, err)
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (c *LoginThrottleUseCase) registerFailure(ctx context.Context, scope string, identifier string) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+	throttle := new(entity.LoginThrottle)
+	if err := c.LoginThrottleRepository.FindByIdentifierForUpdate(tx, throttle, scope, identifier); err != nil {
+		throttle = &entity.LoginThrottle{
+			Scope:      scope,
+			Identifier: identifier,
+		}
+	}
+
+	locked := throttle.LockedUntil != nil && throttle.LockedUntil.After(now)
+	if !locked && throttle.LastFailedAt != nil && now.Sub(*throttle.LastFailedAt) > c.window() {
+		throttle.Failures = 0
+	}
+
+	throttle.Failures++
+	throttle.LastFailedAt = &now
+	if over := throttle.Failures - c.maxAttempts(scope); over >= 0 {
+		lockedUntil := now.Add(c.lockoutDuration(over))
+		throttle.LockedUntil = &lockedUntil
+	}
+
+	if err := c.LoginThrottleRepository.Save(tx, throttle); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (c *LoginThrottleUseCase) identifiers(email string, ip string) map[string]string {
+	identifiers := make(map[string]string)
+	if email = normalizeEmail(email); email != "" {
+		identifiers[LoginThrottleScopeAccount] = email
+	}
+	if ip != "" {
+		identifiers[LoginThrottleScopeIP] = ip
+	}
+	return identifiers
+}
+
+// lockoutDuration doubles the base lockout for every attempt over the limit, capped at LOGIN_LOCKOUT_MAX.
+func (c *LoginThrottleUseCase) lockoutDuration(over int) time.Duration {
+	base := configSeconds(c.Config, "LOGIN_LOCKOUT_BASE", time.Minute)
+	max := configSeconds(c.Config, "LOGIN_LOCKOUT_MAX", time.Hour)
+
+	if over > 30 {
+		return max
+	}
+	if duration := base * time.Duration(1<<over); duration < max {
+		return duration
+	}
+	return max
+}
+
+func (c *LoginThrottleUseCase) maxAttempts(scope string) int {
+	key, fallback := "LOGIN_MAX_ATTEMPTS", 5
+	if scope == LoginThrottleScopeIP {
+		key, fallback = "LOGIN_IP_MAX_ATTEMPTS", 20
+	}
+	if value := c.Config.GetInt(key); value > 0 {
+		return value
+	}
+	return fallback
+}
+
+func (c *LoginThrottleUseCase) window() time.Duration {
+	return configSeconds(c.Config, "LOGIN_ATTEMPT_WINDOW", 15*time.Minute)
+}
+
+func configSeconds(config *viper.Viper, key string, fallback time.Duration) time.Duration {
+	if value := config.GetInt(key); value > 0 {
+		return time.Second * time.Duration(value)
+	}
+	return fallback
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
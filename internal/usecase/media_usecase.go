@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/gateway/storage"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MediaUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	Validate        *validator.Validate
+	Config          *viper.Viper
+	MediaRepository *repository.MediaRepository
+	Storage         storage.Storage
+}
+
+func NewMediaUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, mediaRepository *repository.MediaRepository, storage storage.Storage,
+) *MediaUseCase {
+	return &MediaUseCase{
+		DB:              db,
+		Log:             logger,
+		Validate:        validate,
+		Config:          config,
+		MediaRepository: mediaRepository,
+		Storage:         storage,
+	}
+}
+
+// Upload stores an image of at most MEDIA_MAX_SIZE_MB. The type is sniffed from the content, the file
+// name and the type sent by the client are not trusted. The metadata is stripped, and JPEG and PNG
+// images get a smaller variant for every MEDIA_VARIANT_WIDTHS width below their own, with a WebP
+// copy of each when those come out smaller.
+func (c *MediaUseCase) Upload(ctx context.Context, request *model.UploadMediaRequest) (*model.MediaResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid upload request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	maxSize := int64(c.configInt("MEDIA_MAX_SIZE_MB", 10)) << 20
+	if request.Size > maxSize {
+		return nil, fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("File is larger than %d MB", maxSize>>20))
+	}
+	// The announced size may lie, one byte past the limit is enough to tell.
+	body, err := io.ReadAll(io.LimitReader(request.File, maxSize+1))
+	if err != nil {
+		c.Log.Warnf("Failed to read upload : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("File is larger than %d MB", maxSize>>20))
+	}
+
+	contentType, width, height, err := helper.SniffImage(body)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType, "Only JPEG, PNG, GIF and WebP images are accepted")
+	}
+	if width*height > c.configInt("MEDIA_MAX_PIXELS", 40_000_000) {
+		return nil, fiber.NewError(fiber.StatusRequestEntityTooLarge, "Image dimensions are too large")
+	}
+
+	original := &mediaFile{contentType: contentType, body: body, width: width, height: height}
+	variants, err := c.prepare(original)
+	if err != nil {
+		c.Log.Warnf("Failed to process image : %+v", err)
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType, "The image could not be decoded")
+	}
+
+	// The variants are named after the original, <id>-<width>w.
+	id := uuid.New().String()
+	base := time.Now().UTC().Format("2006/01") + "/" + id
+	original.key = base + helper.ImageTypes[original.contentType]
+	for _, variant := range variants {
+		variant.key = fmt.Sprintf("%s-%dw%s", base, variant.width, helper.ImageTypes[variant.contentType])
+	}
+
+	files := append([]*mediaFile{original}, variants...)
+	keys := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := c.Storage.Put(ctx, file.key, file.contentType, file.body); err != nil {
+			c.Log.Warnf("Failed to store upload : %+v", err)
+			removeFiles(c.Storage, c.Log, keys)
+			return nil, fiber.ErrInternalServerError
+		}
+		keys = append(keys, file.key)
+	}
+
+	media := &entity.Media{
+		ID:          id,
+		UserID:      request.UserId,
+		StorageKey:  original.key,
+		URL:         c.Storage.URL(original.key),
+		FileName:    cleanFileName(request.FileName),
+		ContentType: original.contentType,
+		Size:        int64(len(original.body)),
+		Width:       original.width,
+		Height:      original.height,
+	}
+	for _, variant := range variants {
+		media.Variants = append(media.Variants, entity.MediaVariant{
+			StorageKey:  variant.key,
+			URL:         c.Storage.URL(variant.key),
+			ContentType: variant.contentType,
+			Size:        int64(len(variant.body)),
+			Width:       variant.width,
+			Height:      variant.height,
+		})
+	}
+
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.MediaRepository.Create(tx, media); err != nil {
+		c.Log.Warnf("Failed create media : %+v", err)
+		removeFiles(c.Storage, c.Log, keys)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		removeFiles(c.Storage, c.Log, keys)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.MediaToResponse(media), nil
+}
+
+func (c *MediaUseCase) List(ctx context.Context, request *model.SearchMediaRequest) ([]model.MediaResponse, int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, 0, fiber.ErrBadRequest
+	}
+
+	media, total, err := c.MediaRepository.Find(tx, request)
+	if err != nil {
+		c.Log.Warnf("Failed to get media : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.MediaResponse, len(media))
+	for i, item := range media {
+		responses[i] = *converter.MediaToResponse(&item)
+	}
+	return responses, total, nil
+}
+
+// Delete removes an upload of the user with its variants, one still used by a post is kept.
+func (c *MediaUseCase) Delete(ctx context.Context, userId string, id string) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Var(id, "uuid4"); err != nil {
+		c.Log.Warnf("Invalid media id : %+v", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid media id")
+	}
+
+	media := new(entity.Media)
+	if err := c.MediaRepository.FindByIdAndUserId(tx, media, id, userId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fiber.ErrNotFound
+		}
+		c.Log.Warnf("Failed find media : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	used, err := c.MediaRepository.CountUsage(tx, media.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to count media usage : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	if used > 0 {
+		return fiber.NewError(fiber.StatusConflict, "Media is used by a post")
+	}
+
+	if err := c.MediaRepository.DeleteVariants(tx, media.ID); err != nil {
+		c.Log.Warnf("Failed delete media variants : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	if err := c.MediaRepository.Delete(tx, media); err != nil {
+		c.Log.Warnf("Failed delete media : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	keys := []string{media.StorageKey}
+	for _, variant := range media.Variants {
+		keys = append(keys, variant.StorageKey)
+	}
+	removeFiles(c.Storage, c.Log, keys)
+	return nil
+}
+
+// mediaFile is an image about to be stored, the original upload or one of its variants.
+type mediaFile struct {
+	key         string
+	contentType string
+	body        []byte
+	width       int
+	height      int
+}
+
+// prepare strips the metadata of original and renders its variants. A JPEG that EXIF says to rotate is
+// re-encoded upright, as the orientation goes away with the metadata. GIF is left alone since its
+// variants would lose the animation, and WebP cannot be decoded without a library outside the
+// standard one, so neither gets variants. A WebP whose metadata cannot be stripped is refused, while
+// a JPEG or PNG is then re-encoded, the original bytes are never stored with their metadata.
+//
+// The WebP variants are lossless, they beat PNG but rarely a JPEG photo. They are only kept when
+// every one of them is smaller than the variant of the same width, so a WebP srcset is never a
+// worse pick nor missing widths.
+func (c *MediaUseCase) prepare(original *mediaFile) ([]*mediaFile, error) {
+	if original.contentType != "image/jpeg" && original.contentType != "image/png" {
+		body, err := helper.StripImageMetadata(original.contentType, original.body)
+		if err != nil {
+			return nil, err
+		}
+		original.body = body
+		return nil, nil
+	}
+
+	img, err := helper.DecodeImage(original.body)
+	if err != nil {
+		return nil, err
+	}
+	quality := c.configInt("MEDIA_JPEG_QUALITY", 82)
+
+	if original.contentType == "image/jpeg" && helper.ImageOrientation(original.body) > 1 {
+		if original.body, original.contentType, err = helper.EncodeImage(img, max(quality, 90)); err != nil {
+			return nil, err
+		}
+		original.width, original.height = img.Rect.Dx(), img.Rect.Dy()
+	} else if body, err := helper.StripImageMetadata(original.contentType, original.body); err == nil {
+		original.body = body
+	} else {
+		c.Log.Warnf("Failed to strip image metadata, re-encoding : %+v", err)
+		if original.body, err = helper.ReencodeImage(img, original.contentType, max(quality, 90)); err != nil {
+			return nil, err
+		}
+		original.width, original.height = img.Rect.Dx(), img.Rect.Dy()
+	}
+
+	var variants, webpVariants []*mediaFile
+	useWebP := true
+	for _, width := range c.variantWidths() {
+		if width >= original.width {
+			break
+		}
+		resized := helper.ResizeImage(img, width)
+		body, contentType, err := helper.EncodeImage(resized, quality)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, &mediaFile{
+			contentType: contentType,
+			body:        body,
+			width:       resized.Rect.Dx(),
+			height:      resized.Rect.Dy(),
+		})
+
+		if !useWebP {
+			continue
+		}
+		webpBody, err := helper.EncodeWebP(resized)
+		if err != nil {
+			return nil, err
+		}
+		if len(webpBody) >= len(body) {
+			useWebP, webpVariants = false, nil
+			continue
+		}
+		webpVariants = append(webpVariants, &mediaFile{
+			contentType: "image/webp",
+			body:        webpBody,
+			width:       resized.Rect.Dx(),
+			height:      resized.Rect.Dy(),
+		})
+	}
+	return append(variants, webpVariants...), nil
+}
+
+// variantWidths is MEDIA_VARIANT_WIDTHS sorted without duplicates.
+func (c *MediaUseCase) variantWidths() []int {
+	value := c.Config.GetString("MEDIA_VARIANT_WIDTHS")
+	if value == "" {
+		value = "320,640,1024,1600"
+	}
+
+	var widths []int
+	for _, item := range strings.Split(value, ",") {
+		if width, err := strconv.Atoi(strings.TrimSpace(item)); err == nil && width > 0 && !slices.Contains(widths, width) {
+			widths = append(widths, width)
+		}
+	}
+	slices.Sort(widths)
+	return widths
+}
+
+// removeFiles only logs a failure, an orphaned file is harmless while a failed request is not.
+func removeFiles(store storage.Storage, log *logrus.Logger, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(context.Background(), key); err != nil {
+			log.Warnf("Failed to remove stored file '%s' : %+v", key, err)
+		}
+	}
+}
+
+func (c *MediaUseCase) configInt(key string, fallback int) int {
+	if value := c.Config.GetInt(key); value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// cleanFileName keeps the base name of an uploaded file for display, without any client path.
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "upload"
+	}
+	return truncate(name, 255)
+}
@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/gateway/mail"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type NotificationUseCase struct {
+	DB                     *gorm.DB
+	Log                    *logrus.Logger
+	Validate               *validator.Validate
+	Config                 *viper.Viper
+	NotificationRepository *repository.NotificationRepository
+	FollowRepository       *repository.FollowRepository
+	UserRepository         *repository.UserRepository
+	Mailer                 mail.Mailer
+}
+
+func NewNotificationUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, notificationRepository *repository.NotificationRepository, followRepository *repository.FollowRepository, userRepository *repository.UserRepository, mailer mail.Mailer,
+) *NotificationUseCase {
+	return &NotificationUseCase{
+		DB:                     db,
+		Log:                    logger,
+		Validate:               validate,
+		Config:                 config,
+		NotificationRepository: notificationRepository,
+		FollowRepository:       followRepository,
+		UserRepository:         userRepository,
+		Mailer:                 mailer,
+	}
+}
+
+// Notify stores the notifications in the transaction of the action that caused them, skipping
+// the recipients who turned the type off and the actor itself.
+func (c *NotificationUseCase) Notify(tx *gorm.DB, notificationType string, notifications ...*entity.Notification) error {
+	userIds := make([]string, len(notifications))
+	for i, notification := range notifications {
+		userIds[i] = notification.UserID
+	}
+	disabled, err := c.NotificationRepository.FindDisabledUserIds(tx, notificationType, userIds)
+	if err != nil {
+		return err
+	}
+
+	wanted := make([]*entity.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if disabled[notification.UserID] || notification.UserID == notification.ActorID {
+			continue
+		}
+		notification.Type = notificationType
+		wanted = append(wanted, notification)
+	}
+	return c.NotificationRepository.CreateAll(tx, wanted)
+}
+
+// NotifyPostPublished tells the followers of the author about a new post.
+func (c *NotificationUseCase) NotifyPostPublished(tx *gorm.DB, post *entity.Post, author *entity.User) error {
+	followerIds, err := c.FollowRepository.FindFollowerIds(tx, author.ID)
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]*entity.Notification, len(followerIds))
+	for i, followerId := range followerIds {
+		notifications[i] = &entity.Notification{
+			UserID:  followerId,
+			ActorID: author.ID,
+			Message: truncate(fmt.Sprintf("%s published \"%s\"", author.Name, post.Title), 255),
+			Link:    "/post/" + post.Slug,
+		}
+	}
+	return c.Notify(tx, entity.NotificationTypePostPublished, notifications...)
+}
+
+func (c *NotificationUseCase) List(ctx context.Context, request *model.SearchNotificationRequest) (*model.NotificationListResponse, int64, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, 0, fiber.ErrBadRequest
+	}
+
+	notifications, total, err := c.NotificationRepository.Find(tx, request)
+	if err != nil {
+		c.Log.Warnf("Failed to get notifications : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	unread, err := c.NotificationRepository.CountUnread(tx, request.UserId)
+	if err != nil {
+		c.Log.Warnf("Failed to count unread notifications : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, 0, fiber.ErrInternalServerError
+	}
+
+	response := &model.NotificationListResponse{
+		UnreadCount:   unread,
+		Notifications: make([]*model.NotificationResponse, len(notifications)),
+	}
+	for i, notification := range notifications {
+		response.Notifications[i] = converter.NotificationToResponse(&notification)
+	}
+	return response, total, nil
+}
+
+func (c *NotificationUseCase) MarkRead(ctx context.Context, userId string, id uint) (*model.NotificationResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	notification := new(entity.Notification)
+	if err := c.NotificationRepository.FindByIdAndUserId(tx, notification, id, userId); err != nil {
+		c.Log.Warnf("Failed find notification : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+		if err := c.NotificationRepository.Save(tx, notification); err != nil {
+			c.Log.Warnf("Failed save notification : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.NotificationToResponse(notification), nil
+}
+
+func (c *NotificationUseCase) MarkAllRead(ctx context.Context, userId string) (*model.MarkNotificationsReadResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	marked, err := c.NotificationRepository.MarkAllRead(tx, userId, time.Now())
+	if err != nil {
+		c.Log.Warnf("Failed mark notifications read : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.MarkNotificationsReadResponse{Marked: marked}, nil
+}
+
+// GetPreferences returns a preference for every type, the types a user never changed have the defaults.
+func (c *NotificationUseCase) GetPreferences(ctx context.Context, userId string) ([]*model.NotificationPreferenceResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	preferences, err := c.preferences(tx, userId)
+	if err != nil {
+		c.Log.Warnf("Failed to get notification preferences : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return preferencesToResponse(preferences), nil
+}
+
+func (c *NotificationUseCase) UpdatePreferences(ctx context.Context, request *model.UpdateNotificationPreferencesRequest) ([]*model.NotificationPreferenceResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	preferences, err := c.preferences(tx, request.UserId)
+	if err != nil {
+		c.Log.Warnf("Failed to get notification preferences : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	for _, item := range request.Preferences {
+		preference := preferences[item.Type]
+		if item.Enabled != nil {
+			preference.Enabled = *item.Enabled
+		}
+		if item.Email != nil {
+			preference.Email = *item.Email
+		}
+		if err := c.NotificationRepository.SavePreference(tx, preference); err != nil {
+			c.Log.Warnf("Failed save notification preference : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return preferencesToResponse(preferences), nil
+}
+
+// SendDigests emails every user one summary of the unread notifications they asked to get by email.
+func (c *NotificationUseCase) SendDigests(ctx context.Context) error {
+	db := c.DB.WithContext(ctx)
+
+	userIds, err := c.NotificationRepository.FindDigestUserIds(db)
+	if err != nil {
+		c.Log.Warnf("Failed to find notification digest users : %+v", err)
+		return err
+	}
+
+	for _, userId := range userIds {
+		if err := c.sendDigest(ctx, userId); err != nil {
+			c.Log.Warnf("Failed to send notification digest to '%s' : %+v", userId, err)
+		}
+	}
+	return nil
+}
+
+func (c *NotificationUseCase) sendDigest(ctx context.Context, userId string) error {
+	db := c.DB.WithContext(ctx)
+
+	// Deleted users are not found, their digest waits until the account is restored or purged.
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(db, user, userId); err != nil {
+		return nil
+	}
+
+	notifications, err := c.NotificationRepository.FindDigest(db, userId)
+	if err != nil || len(notifications) == 0 {
+		return err
+	}
+
+	baseURL := strings.TrimRight(c.Config.GetString("APP_BASE_URL"), "/")
+	var body strings.Builder
+	body.WriteString(fmt.Sprintf("Hi %s,\n\nHere is what happened since your last digest:\n\n", user.Name))
+	ids := make([]uint, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+		body.WriteString("- " + notification.Message + "\n")
+		if notification.Link != "" {
+			body.WriteString("  " + baseURL + notification.Link + "\n")
+		}
+	}
+	body.WriteString("\nYou can change which notifications you get by email in your notification preferences.\n")
+
+	message := &mail.Message{
+		To:      user.Email,
+		Subject: fmt.Sprintf("You have %d new notifications", len(notifications)),
+		Body:    body.String(),
+	}
+	if err := c.Mailer.Send(ctx, message); err != nil {
+		return err
+	}
+
+	return c.NotificationRepository.MarkEmailed(db, ids, time.Now())
+}
+
+func (c *NotificationUseCase) preferences(tx *gorm.DB, userId string) (map[string]*entity.NotificationPreference, error) {
+	stored, err := c.NotificationRepository.FindPreferences(tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[string]*entity.NotificationPreference, len(entity.NotificationTypes))
+	for _, notificationType := range entity.NotificationTypes {
+		preferences[notificationType] = &entity.NotificationPreference{UserID: userId, Type: notificationType, Enabled: true}
+	}
+	for i := range stored {
+		if _, ok := preferences[stored[i].Type]; ok {
+			preferences[stored[i].Type] = &stored[i]
+		}
+	}
+	return preferences, nil
+}
+
+func preferencesToResponse(preferences map[string]*entity.NotificationPreference) []*model.NotificationPreferenceResponse {
+	response := make([]*model.NotificationPreferenceResponse, len(entity.NotificationTypes))
+	for i, notificationType := range entity.NotificationTypes {
+		response[i] = converter.NotificationPreferenceToResponse(preferences[notificationType])
+	}
+	return response
+}
+
+func truncate(value string, length int) string {
+	if runes := []rune(value); len(runes) > length {
+		return string(runes[:length-1]) + "…"
+	}
+	return value
+}
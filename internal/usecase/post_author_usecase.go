@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"time"
+)
+
+// PostAuthorUseCase manages the contributors of a post. Only the primary author invites, the invited
+// user accepts, and either of them can end the credit.
+type PostAuthorUseCase struct {
+	DB                   *gorm.DB
+	Log                  *logrus.Logger
+	Validate             *validator.Validate
+	PostAuthorRepository *repository.PostAuthorRepository
+	PostRepository       *repository.PostRepository
+	UserRepository       *repository.UserRepository
+	Notification         *NotificationUseCase
+}
+
+func NewPostAuthorUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, postAuthorRepository *repository.PostAuthorRepository, postRepository *repository.PostRepository, userRepository *repository.UserRepository, notification *NotificationUseCase,
+) *PostAuthorUseCase {
+	return &PostAuthorUseCase{
+		DB:                   db,
+		Log:                  logger,
+		Validate:             validate,
+		PostAuthorRepository: postAuthorRepository,
+		PostRepository:       postRepository,
+		UserRepository:       userRepository,
+		Notification:         notification,
+	}
+}
+
+// List returns the contributors of a post with the pending invitations, only the people credited or invited on it may see them.
+func (c *PostAuthorUseCase) List(ctx context.Context, request *model.PostAuthorRequest) ([]model.PostAuthorResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	post, err := c.findPost(tx, request.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	authors, err := c.PostAuthorRepository.FindAllByPostId(tx, post.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to get post authors : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	involved := post.UserID == request.UserId
+	for _, author := range authors {
+		involved = involved || author.UserID == request.UserId
+	}
+	if !involved {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Only the authors of the post can see its invitations")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	post.Authors = authors
+	return converter.PostAuthorsToResponse(post), nil
+}
+
+// ListInvitations returns the invitations of the user that are still waiting for an answer.
+func (c *PostAuthorUseCase) ListInvitations(ctx context.Context, userId string) ([]*model.PostInvitationResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	authors, err := c.PostAuthorRepository.FindPendingByUserId(tx, userId)
+	if err != nil {
+		c.Log.Warnf("Failed to get invitations : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]*model.PostInvitationResponse, len(authors))
+	for i, author := range authors {
+		response[i] = converter.PostInvitationToResponse(&author)
+	}
+	return response, nil
+}
+
+// Invite asks a user to be credited on the post with a role, the credit shows once the user accepts.
+func (c *PostAuthorUseCase) Invite(ctx context.Context, request *model.InvitePostAuthorRequest) (*model.PostAuthorResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	post, err := c.findPost(tx, request.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != request.UserId {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Only the primary author can invite contributors")
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByUsername(tx, user, request.Username); err != nil {
+		c.Log.Warnf("Failed find user by username '%s' : %+v", request.Username, err)
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+	if user.ID == post.UserID {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The primary author is already credited")
+	}
+
+	if err := c.PostAuthorRepository.FindByPostIdAndUserId(tx, new(entity.PostAuthor), post.ID, user.ID); err == nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "User is already credited or invited")
+	}
+
+	author := &entity.PostAuthor{
+		PostID:    post.ID,
+		UserID:    user.ID,
+		Role:      request.Role,
+		InvitedBy: request.UserId,
+	}
+	if err := c.PostAuthorRepository.Create(tx, author); err != nil {
+		c.Log.Warnf("Failed to create post author : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	err = c.Notification.Notify(tx, entity.NotificationTypeCoauthorInvited, &entity.Notification{
+		UserID:  user.ID,
+		ActorID: post.UserID,
+		Message: truncate(fmt.Sprintf("%s invited you as %s of \"%s\"", post.User.Name, request.Role, post.Title), 255),
+		Link:    "/users/me/invitations",
+	})
+	if err != nil {
+		c.Log.Warnf("Failed to notify invitation : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	author.User = *user
+	return converter.PostAuthorToResponse(author), nil
+}
+
+// Accept credits the user on the post it was invited to, accepting twice keeps the first acceptance.
+func (c *PostAuthorUseCase) Accept(ctx context.Context, request *model.PostAuthorRequest) (*model.PostAuthorResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	post, err := c.findPost(tx, request.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	author := new(entity.PostAuthor)
+	if err := c.PostAuthorRepository.FindByPostIdAndUserId(tx, author, post.ID, request.UserId); err != nil {
+		c.Log.Warnf("Failed to find invitation : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, "No invitation to this post")
+	}
+
+	if author.AcceptedAt == nil {
+		now := time.Now()
+		author.AcceptedAt = &now
+		if err := c.PostAuthorRepository.Save(tx, author); err != nil {
+			c.Log.Warnf("Failed to accept invitation : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.UserRepository.FindById(tx, &author.User, request.UserId); err != nil {
+		c.Log.Warnf("Failed to find user : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return converter.PostAuthorToResponse(author), nil
+}
+
+// Remove ends the credit or invitation of Username on the post. The primary author can remove anyone,
+// the others only themselves, which is also how an invitation is declined.
+func (c *PostAuthorUseCase) Remove(ctx context.Context, request *model.PostAuthorRequest) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return fiber.ErrBadRequest
+	}
+
+	post, err := c.findPost(tx, request.Slug)
+	if err != nil {
+		return err
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByUsername(tx, user, request.Username); err != nil {
+		c.Log.Warnf("Failed find user by username '%s' : %+v", request.Username, err)
+		return fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+	if post.UserID != request.UserId && user.ID != request.UserId {
+		return fiber.NewError(fiber.StatusForbidden, "Only the primary author can remove other contributors")
+	}
+
+	author := new(entity.PostAuthor)
+	if err := c.PostAuthorRepository.FindByPostIdAndUserId(tx, author, post.ID, user.ID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "User is not credited on this post")
+	}
+	if err := c.PostAuthorRepository.Delete(tx, author); err != nil {
+		c.Log.Warnf("Failed to delete post author : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+// findPost loads the post with its primary author.
+func (c *PostAuthorUseCase) findPost(tx *gorm.DB, slug string) (*entity.Post, error) {
+	post := new(entity.Post)
+	err := tx.
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("ID", "Name", "Username")
+		}).
+		Where("slug = ?", slug).
+		Take(post).Error
+	if err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", slug, err)
+		return nil, fiber.ErrNotFound
+	}
+	return post, nil
+}
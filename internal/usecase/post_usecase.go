@@ -2,37 +2,60 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"go-blog/internal/entity"
+	"go-blog/internal/gateway/realtime"
 	"go-blog/internal/helper"
 	"go-blog/internal/model"
 	"go-blog/internal/model/converter"
 	"go-blog/internal/repository"
 	"gorm.io/gorm"
+	"slices"
 	"strings"
+	"time"
 )
 
 type PostUseCase struct {
-	DB             *gorm.DB
-	Log            *logrus.Logger
-	Validate       *validator.Validate
-	PostRepository *repository.PostRepository
-	TagRepository  *repository.TagRepository
-	UserRepository *repository.UserRepository
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	Config                    *viper.Viper
+	PostRepository            *repository.PostRepository
+	TagRepository             *repository.TagRepository
+	UserRepository            *repository.UserRepository
+	UsernameHistoryRepository *repository.UsernameHistoryRepository
+	ReactionRepository        *repository.ReactionRepository
+	BookmarkRepository        *repository.BookmarkRepository
+	MediaRepository           *repository.MediaRepository
+	SeriesRepository          *repository.SeriesRepository
+	PostAuthorRepository      *repository.PostAuthorRepository
+	Notification              *NotificationUseCase
+	Publisher                 realtime.Publisher
 }
 
 func NewPostUseCase(
-	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, postRepository *repository.PostRepository, tagRepository *repository.TagRepository, userRepository *repository.UserRepository,
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, postRepository *repository.PostRepository, tagRepository *repository.TagRepository, userRepository *repository.UserRepository, usernameHistoryRepository *repository.UsernameHistoryRepository, reactionRepository *repository.ReactionRepository, bookmarkRepository *repository.BookmarkRepository, mediaRepository *repository.MediaRepository, seriesRepository *repository.SeriesRepository, postAuthorRepository *repository.PostAuthorRepository, notification *NotificationUseCase, publisher realtime.Publisher,
 ) *PostUseCase {
 	return &PostUseCase{
-		DB:             db,
-		Log:            logger,
-		Validate:       validate,
-		PostRepository: postRepository,
-		TagRepository:  tagRepository,
-		UserRepository: userRepository,
+		DB:                        db,
+		Log:                       logger,
+		Validate:                  validate,
+		Config:                    config,
+		PostRepository:            postRepository,
+		TagRepository:             tagRepository,
+		UserRepository:            userRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+		ReactionRepository:        reactionRepository,
+		BookmarkRepository:        bookmarkRepository,
+		MediaRepository:           mediaRepository,
+		SeriesRepository:          seriesRepository,
+		PostAuthorRepository:      postAuthorRepository,
+		Notification:              notification,
+		Publisher:                 publisher,
 	}
 }
 
@@ -45,48 +68,57 @@ func (c *PostUseCase) Create(ctx context.Context, userId string, request *model.
 		return nil, fiber.ErrBadRequest
 	}
 
-	var tags []*entity.Tag
-	for _, item := range request.Tags {
-		if item.ID == 0 && len(item.Name) < 0 {
-			item.Name = "something"
-		}
-		if err := c.Validate.Struct(item); err != nil {
-			c.Log.Warnf("Invalid tags request on body: %+v", err)
-			return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid tags request on body")
-		}
-		if item.ID == 0 {
-			newTagName := strings.TrimSpace(item.Name)
-			slug := helper.GenerateSlug(newTagName)
-			newTag := &entity.Tag{
-				Name: newTagName,
-				Slug: slug,
-			}
-
-			if err := c.TagRepository.Create(tx, newTag); err != nil {
-				c.Log.Warnf("Failed create tag to database : %+v", err)
-				return nil, fiber.ErrInternalServerError
-			}
+	tags, err := c.findTags(tx, request.Tags)
+	if err != nil {
+		return nil, err
+	}
 
-			tags = append(tags, newTag)
-		} else {
-			existTag := new(entity.Tag)
-			if err := c.TagRepository.FindById(tx, existTag, item.ID); err != nil {
-				c.Log.Warnf("Failed to find tag with ID %v : %+v", item.ID, err)
-				return nil, fiber.ErrInternalServerError
-			}
-			tags = append(tags, existTag)
+	var cover *entity.Media
+	if request.CoverMediaID != "" {
+		cover = new(entity.Media)
+		if err := c.MediaRepository.FindByIdAndUserId(tx, cover, request.CoverMediaID, userId); err != nil {
+			c.Log.Warnf("Failed to find cover media %s : %+v", request.CoverMediaID, err)
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Cover media not found among your uploads")
 		}
 	}
 
+	gallery, err := c.findGallery(tx, userId, request.Gallery)
+	if err != nil {
+		return nil, err
+	}
+
 	newTitle := strings.TrimSpace(request.Title)
 	slug := helper.GenerateSlug(newTitle)
 
+	// There are no drafts, a post is published as soon as it is created.
+	publishedAt := time.Now()
 	post := &entity.Post{
-		Title:   newTitle,
-		Slug:    slug,
-		Content: request.Content,
-		UserID:  userId,
-		Tags:    tags,
+		Title:       newTitle,
+		Slug:        slug,
+		Content:     request.Content,
+		UserID:      userId,
+		Tags:        tags,
+		PublishedAt: &publishedAt,
+
+		MetaTitle:       strings.TrimSpace(request.MetaTitle),
+		MetaDescription: strings.TrimSpace(request.MetaDescription),
+		CanonicalURL:    request.CanonicalURL,
+		SocialImage:     request.SocialImage,
+		NoIndex:         request.NoIndex,
+
+		CoverAlt:     strings.TrimSpace(request.CoverAlt),
+		CoverCaption: strings.TrimSpace(request.CoverCaption),
+	}
+	if cover != nil {
+		post.CoverMediaID = &cover.ID
+	}
+	for i, image := range request.Gallery {
+		post.Gallery = append(post.Gallery, entity.PostImage{
+			MediaID:  image.MediaID,
+			Position: i + 1,
+			Alt:      strings.TrimSpace(image.Alt),
+			Caption:  strings.TrimSpace(image.Caption),
+		})
 	}
 
 	if err := c.PostRepository.Create(tx, post); err != nil {
@@ -100,44 +132,176 @@ func (c *PostUseCase) Create(ctx context.Context, userId string, request *model.
 		return nil, fiber.ErrInternalServerError
 	}
 
+	post.CoverMedia = cover
+	for i := range post.Gallery {
+		post.Gallery[i].Media = gallery[post.Gallery[i].MediaID]
+	}
 	post.User = entity.User{
 		ID:       user.ID,
 		Name:     user.Name,
 		Username: user.Username,
 	}
 
+	if err := c.Notification.NotifyPostPublished(tx, post, user); err != nil {
+		c.Log.Warnf("Failed to notify followers : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed commit transaction : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return converter.PostToResponse(post), nil
+	response := converter.PostToResponse(post)
+	publish(ctx, c.Publisher, c.Log, realtime.TopicPosts, EventPostCreated, response)
+	return response, nil
 }
 
 func (c *PostUseCase) List(ctx context.Context, request *model.SearchPostRequest) ([]model.PostResponse, int64, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	if request.Bookmarked && request.ViewerId == "" {
+		return nil, 0, fiber.NewError(fiber.StatusUnauthorized, "Login to see your bookmarks")
+	}
+
+	if request.Username != "" {
+		if err := c.UserRepository.FindByUsername(tx, new(entity.User), request.Username); err != nil {
+			if moved := findMovedUsername(tx, c.Config, c.UsernameHistoryRepository, request.Username); moved != nil {
+				return nil, 0, moved
+			}
+		}
+	}
+
 	posts, total, err := c.PostRepository.Find(tx, request)
 	if err != nil {
 		c.Log.Warnf("Failed to get posts : %+v", err)
 		return nil, 0, fiber.ErrInternalServerError
 	}
 
+	response, err := c.toViewerResponses(tx, request.ViewerId, posts)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.WithError(err).Error("failed to commit transaction")
 		return nil, 0, fiber.ErrInternalServerError
 	}
 
-	response := make([]model.PostResponse, len(posts))
-	for i, post := range posts {
-		response[i] = *converter.PostToResponse(&post)
+	return response, total, nil
+}
+
+// Update lets the primary author and the contributors with the author or editor role change a post.
+func (c *PostUseCase) Update(ctx context.Context, request *model.UpdatePostRequest) (*model.PostResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
 	}
 
-	return response, total, nil
+	post := new(entity.Post)
+	if err := tx.Where("slug = ?", request.Slug).Take(post).Error; err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+	if err := c.checkEditor(tx, post, request.UserId); err != nil {
+		return nil, err
+	}
+
+	if title := strings.TrimSpace(request.Title); title != "" {
+		post.Title = title
+	}
+	if request.Content != "" {
+		post.Content = request.Content
+	}
+	if metaTitle := strings.TrimSpace(request.MetaTitle); metaTitle != "" {
+		post.MetaTitle = metaTitle
+	}
+	if metaDescription := strings.TrimSpace(request.MetaDescription); metaDescription != "" {
+		post.MetaDescription = metaDescription
+	}
+	if request.CanonicalURL != "" {
+		post.CanonicalURL = request.CanonicalURL
+	}
+	if request.SocialImage != "" {
+		post.SocialImage = request.SocialImage
+	}
+	if request.NoIndex != nil {
+		post.NoIndex = *request.NoIndex
+	}
+
+	if err := c.PostRepository.Save(tx, post); err != nil {
+		c.Log.Warnf("Failed to update post : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if request.Tags != nil {
+		tags, err := c.findTags(tx, request.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.PostRepository.ReplaceTags(tx, post, tags); err != nil {
+			c.Log.Warnf("Failed to update post tags : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	updated := new(entity.Post)
+	if err := c.PostRepository.FindBySlug(tx, updated, post.Slug); err != nil {
+		c.Log.Warnf("Failed to reload post : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// The primary author hears about edits made by co-authors and editors.
+	if request.UserId != post.UserID {
+		editor := new(entity.User)
+		if err := c.UserRepository.FindById(tx, editor, request.UserId); err != nil {
+			c.Log.Warnf("Failed find editor by id : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		err := c.Notification.Notify(tx, entity.NotificationTypePostEdited, &entity.Notification{
+			UserID:  post.UserID,
+			ActorID: editor.ID,
+			Message: truncate(fmt.Sprintf("%s edited \"%s\"", editor.Name, post.Title), 255),
+			Link:    "/post/" + post.Slug,
+		})
+		if err != nil {
+			c.Log.Warnf("Failed to notify post edit : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	publishPostUpdated(ctx, c.DB, c.Publisher, c.Log, post.ID, "content")
+	return converter.PostToResponse(updated), nil
 }
 
-func (c *PostUseCase) GetBySlug(ctx context.Context, slug string) (*model.PostResponse, error) {
+// checkEditor fails unless the user is the primary author of the post or an accepted author or editor of it.
+func (c *PostUseCase) checkEditor(tx *gorm.DB, post *entity.Post, userId string) error {
+	if post.UserID == userId {
+		return nil
+	}
+
+	author := new(entity.PostAuthor)
+	if err := c.PostAuthorRepository.FindByPostIdAndUserId(tx, author, post.ID, userId); err != nil {
+		return fiber.NewError(fiber.StatusForbidden, "Only the authors and editors of the post can edit it")
+	}
+	if author.AcceptedAt == nil || author.Role == entity.PostAuthorRoleReviewer {
+		return fiber.NewError(fiber.StatusForbidden, "Only the authors and editors of the post can edit it")
+	}
+	return nil
+}
+
+// GetBySlug fills ReactedByMe and BookmarkedByMe when viewerId is set, anonymous callers pass an empty one.
+// A post that is part of a series links to its previous and next published parts.
+func (c *PostUseCase) GetBySlug(ctx context.Context, slug string, viewerId string) (*model.PostResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -147,10 +311,162 @@ func (c *PostUseCase) GetBySlug(ctx context.Context, slug string) (*model.PostRe
 		return nil, fiber.ErrNotFound
 	}
 
+	response, err := c.toViewerResponses(tx, viewerId, []entity.Post{*post})
+	if err != nil {
+		return nil, err
+	}
+	response[0].Meta = postMeta(c.Config, post)
+
+	response[0].Series, err = findPostSeries(tx, c.SeriesRepository, c.PostRepository, post.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to find series of post : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &response[0], nil
+}
+
+// Feed returns the published posts of the authors and tags the user follows, newest first.
+func (c *PostUseCase) Feed(ctx context.Context, request *model.FeedRequest) ([]model.PostResponse, string, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, "", fiber.ErrBadRequest
+	}
+
+	if request.Cursor != "" {
+		publishedAt, id, err := decodeFeedCursor(request.Cursor)
+		if err != nil {
+			c.Log.Warnf("Invalid feed cursor : %+v", err)
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+		}
+		request.BeforePublishedAt = &publishedAt
+		request.BeforeID = id
+	}
+
+	posts, err := c.PostRepository.FindFeed(tx, request)
+	if err != nil {
+		c.Log.Warnf("Failed to get feed : %+v", err)
+		return nil, "", fiber.ErrInternalServerError
+	}
+
+	var nextCursor string
+	if len(posts) > request.Size {
+		posts = posts[:request.Size]
+		last := posts[len(posts)-1]
+		nextCursor = encodeFeedCursor(*last.PublishedAt, last.ID)
+	}
+
+	response, err := c.toViewerResponses(tx, request.UserId, posts)
+	if err != nil {
+		return nil, "", err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, "", fiber.ErrInternalServerError
+	}
+
+	return response, nextCursor, nil
+}
+
+// toViewerResponses converts the posts and marks what viewerId reacted on or bookmarked, anonymous viewers have an empty id.
+func (c *PostUseCase) toViewerResponses(tx *gorm.DB, viewerId string, posts []entity.Post) ([]model.PostResponse, error) {
+	postIds := make([]uint, len(posts))
+	for i, post := range posts {
+		postIds[i] = post.ID
+	}
+
+	reacted, err := c.ReactionRepository.FindTypesByUser(tx, viewerId, postIds)
+	if err != nil {
+		c.Log.Warnf("Failed to get reactions of viewer : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	bookmarked, err := c.BookmarkRepository.FindPostIdsByUser(tx, viewerId, postIds)
+	if err != nil {
+		c.Log.Warnf("Failed to get bookmarks of viewer : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]model.PostResponse, len(posts))
+	for i, post := range posts {
+		response[i] = *converter.PostToResponse(&post)
+		response[i].ReactedByMe = reacted[post.ID]
+		response[i].BookmarkedByMe = bookmarked[post.ID]
+	}
+	return response, nil
+}
+
+// findTags loads the tags picked by id and creates the ones given by name.
+func (c *PostUseCase) findTags(tx *gorm.DB, items []model.CreateTagResponse) ([]*entity.Tag, error) {
+	var tags []*entity.Tag
+	for _, item := range items {
+		if item.ID == 0 && len(item.Name) < 0 {
+			item.Name = "something"
+		}
+		if err := c.Validate.Struct(item); err != nil {
+			c.Log.Warnf("Invalid tags request on body: %+v", err)
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid tags request on body")
+		}
+		if item.ID == 0 {
+			newTagName := strings.TrimSpace(item.Name)
+			slug := helper.GenerateSlug(newTagName)
+			newTag := &entity.Tag{
+				Name: newTagName,
+				Slug: slug,
+			}
+
+			if err := c.TagRepository.Create(tx, newTag); err != nil {
+				c.Log.Warnf("Failed create tag to database : %+v", err)
+				return nil, fiber.ErrInternalServerError
+			}
+
+			tags = append(tags, newTag)
+		} else {
+			existTag := new(entity.Tag)
+			if err := c.TagRepository.FindById(tx, existTag, item.ID); err != nil {
+				c.Log.Warnf("Failed to find tag with ID %v : %+v", item.ID, err)
+				return nil, fiber.ErrInternalServerError
+			}
+			tags = append(tags, existTag)
+		}
+	}
+	return tags, nil
+}
+
+// findGallery loads the media of the gallery images by id, each must be an upload of the user and come once.
+func (c *PostUseCase) findGallery(tx *gorm.DB, userId string, images []model.PostImageRequest) (map[string]entity.Media, error) {
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(images))
+	for i, image := range images {
+		if slices.Contains(ids[:i], image.MediaID) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "An image can only be once in the gallery")
+		}
+		ids[i] = image.MediaID
+	}
+
+	media, err := c.MediaRepository.FindByIdsAndUserId(tx, ids, userId)
+	if err != nil {
+		c.Log.Warnf("Failed to find gallery media : %+v", err)
 		return nil, fiber.ErrInternalServerError
 	}
+	if len(media) != len(ids) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Gallery media not found among your uploads")
+	}
 
-	return converter.PostToResponse(post), nil
+	gallery := make(map[string]entity.Media, len(media))
+	for _, item := range media {
+		gallery[item.ID] = item
+	}
+	return gallery, nil
 }
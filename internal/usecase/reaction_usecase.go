@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/gateway/realtime"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ReactionUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	Validate           *validator.Validate
+	ReactionRepository *repository.ReactionRepository
+	PostRepository     *repository.PostRepository
+	Publisher          realtime.Publisher
+}
+
+func NewReactionUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, reactionRepository *repository.ReactionRepository, postRepository *repository.PostRepository, publisher realtime.Publisher,
+) *ReactionUseCase {
+	return &ReactionUseCase{
+		DB:                 db,
+		Log:                logger,
+		Validate:           validate,
+		ReactionRepository: reactionRepository,
+		PostRepository:     postRepository,
+		Publisher:          publisher,
+	}
+}
+
+// React is idempotent, reacting twice with the same type keeps a single reaction.
+func (c *ReactionUseCase) React(ctx context.Context, request *model.ReactionRequest) (*model.ReactionSummaryResponse, error) {
+	return c.apply(ctx, request, func(tx *gorm.DB, post *entity.Post) error {
+		_, err := c.ReactionRepository.Add(tx, &entity.Reaction{
+			PostID: post.ID,
+			UserID: request.UserId,
+			Type:   request.Type,
+		})
+		return err
+	})
+}
+
+// Unreact is idempotent as well, removing a reaction that does not exist is not an error.
+func (c *ReactionUseCase) Unreact(ctx context.Context, request *model.ReactionRequest) (*model.ReactionSummaryResponse, error) {
+	return c.apply(ctx, request, func(tx *gorm.DB, post *entity.Post) error {
+		_, err := c.ReactionRepository.Remove(tx, post.ID, request.UserId, request.Type)
+		return err
+	})
+}
+
+func (c *ReactionUseCase) apply(ctx context.Context, request *model.ReactionRequest, change func(tx *gorm.DB, post *entity.Post) error) (*model.ReactionSummaryResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid reaction type")
+	}
+
+	post := new(entity.Post)
+	if err := tx.Where("slug = ?", request.Slug).Take(post).Error; err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := change(tx, post); err != nil {
+		c.Log.Warnf("Failed to update reaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	counts, err := c.ReactionRepository.FindCountsByPostId(tx, post.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to get reaction counts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	reacted, err := c.ReactionRepository.FindTypesByUser(tx, request.UserId, []uint{post.ID})
+	if err != nil {
+		c.Log.Warnf("Failed to get reactions of user : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := &model.ReactionSummaryResponse{
+		Reactions:   converter.ReactionCountsToResponse(counts),
+		ReactedByMe: reacted[post.ID],
+	}
+	if response.ReactedByMe == nil {
+		response.ReactedByMe = []string{}
+	}
+
+	publish(ctx, c.Publisher, c.Log, realtime.TopicPost(post.Slug), EventPostUpdated, &model.PostUpdatedEvent{
+		Slug:         post.Slug,
+		Change:       "reactions",
+		CommentCount: post.CommentCount,
+		Reactions:    response.Reactions,
+	})
+	return response, nil
+}
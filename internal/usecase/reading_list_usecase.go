@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"strings"
+)
+
+type ReadingListUseCase struct {
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	Config                    *viper.Viper
+	ReadingListRepository     *repository.ReadingListRepository
+	PostRepository            *repository.PostRepository
+	UserRepository            *repository.UserRepository
+	UsernameHistoryRepository *repository.UsernameHistoryRepository
+}
+
+func NewReadingListUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, readingListRepository *repository.ReadingListRepository, postRepository *repository.PostRepository, userRepository *repository.UserRepository, usernameHistoryRepository *repository.UsernameHistoryRepository,
+) *ReadingListUseCase {
+	return &ReadingListUseCase{
+		DB:                        db,
+		Log:                       logger,
+		Validate:                  validate,
+		Config:                    config,
+		ReadingListRepository:     readingListRepository,
+		PostRepository:            postRepository,
+		UserRepository:            userRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+	}
+}
+
+// ListOwn returns every list of the logged in user, private ones included.
+func (c *ReadingListUseCase) ListOwn(ctx context.Context, userId string) ([]*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	return c.list(tx, userId, false)
+}
+
+// ListByUsername returns the public lists of an author.
+func (c *ReadingListUseCase) ListByUsername(ctx context.Context, username string) ([]*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByUsername(tx, user, username); err != nil {
+		c.Log.Warnf("Failed find user by username '%s' : %+v", username, err)
+		if moved := findMovedUsername(tx, c.Config, c.UsernameHistoryRepository, username); moved != nil {
+			return nil, moved
+		}
+		return nil, fiber.ErrNotFound
+	}
+
+	return c.list(tx, user.ID, true)
+}
+
+func (c *ReadingListUseCase) list(tx *gorm.DB, userId string, publicOnly bool) ([]*model.ReadingListResponse, error) {
+	lists, err := c.ReadingListRepository.FindAllByUserId(tx, userId, publicOnly)
+	if err != nil {
+		c.Log.Warnf("Failed to get reading lists : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	listIds := make([]uint, len(lists))
+	for i, list := range lists {
+		listIds[i] = list.ID
+	}
+	counts, err := c.ReadingListRepository.CountPostsByListIds(tx, listIds)
+	if err != nil {
+		c.Log.Warnf("Failed to count reading list posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]*model.ReadingListResponse, len(lists))
+	for i, list := range lists {
+		response[i] = converter.ReadingListToResponse(&list, counts[list.ID])
+	}
+	return response, nil
+}
+
+// Get returns a list with its posts in order, private lists are only visible to their owner.
+func (c *ReadingListUseCase) Get(ctx context.Context, request *model.GetReadingListRequest) (*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	list := new(entity.ReadingList)
+	if err := c.ReadingListRepository.FindById(tx, list, request.ID); err != nil {
+		c.Log.Warnf("Failed to find reading list : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+	if list.Visibility != entity.ReadingListVisibilityPublic && list.UserID != request.ViewerId {
+		return nil, fiber.ErrNotFound
+	}
+
+	response, err := c.withPosts(tx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return response, nil
+}
+
+func (c *ReadingListUseCase) Create(ctx context.Context, request *model.CreateReadingListRequest) (*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	list := &entity.ReadingList{
+		UserID:      request.UserId,
+		Name:        strings.TrimSpace(request.Name),
+		Description: strings.TrimSpace(request.Description),
+		Visibility:  request.Visibility,
+	}
+	if list.Visibility == "" {
+		list.Visibility = entity.ReadingListVisibilityPrivate
+	}
+
+	if err := c.ReadingListRepository.Create(tx, list); err != nil {
+		c.Log.Warnf("Failed to create reading list : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.ReadingListToResponse(list, 0), nil
+}
+
+func (c *ReadingListUseCase) Update(ctx context.Context, request *model.UpdateReadingListRequest) (*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	list, err := c.findOwned(tx, request.ID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(request.Name); name != "" {
+		list.Name = name
+	}
+	if description := strings.TrimSpace(request.Description); description != "" {
+		list.Description = description
+	}
+	if request.Visibility != "" {
+		list.Visibility = request.Visibility
+	}
+
+	if err := c.ReadingListRepository.Save(tx, list); err != nil {
+		c.Log.Warnf("Failed to update reading list : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response, err := c.withPosts(tx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return response, nil
+}
+
+func (c *ReadingListUseCase) Delete(ctx context.Context, listId uint, userId string) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	list, err := c.findOwned(tx, listId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := c.ReadingListRepository.DeleteWithItems(tx, list); err != nil {
+		c.Log.Warnf("Failed to delete reading list : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+func (c *ReadingListUseCase) AddPost(ctx context.Context, request *model.ReadingListPostRequest) (*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	list, err := c.findOwned(tx, request.ListID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	post := new(entity.Post)
+	if err := tx.Where("slug = ?", request.Slug).Take(post).Error; err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if _, err := c.ReadingListRepository.InsertItem(tx, list.ID, post.ID, request.Position); err != nil {
+		c.Log.Warnf("Failed to add post to reading list : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return c.touchAndCommit(tx, list)
+}
+
+func (c *ReadingListUseCase) RemovePost(ctx context.Context, request *model.ReadingListPostRequest) (*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	list, err := c.findOwned(tx, request.ListID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unscoped so a post that was deleted meanwhile can still be taken out of the list.
+	post := new(entity.Post)
+	if err := tx.Unscoped().Where("slug = ?", request.Slug).Take(post).Error; err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	removed, err := c.ReadingListRepository.RemoveItem(tx, list.ID, post.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to remove post from reading list : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if !removed {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Post is not in this list")
+	}
+
+	return c.touchAndCommit(tx, list)
+}
+
+// Reorder applies the full new order of a list, every post of the list has to be named exactly once.
+func (c *ReadingListUseCase) Reorder(ctx context.Context, request *model.ReorderReadingListRequest) (*model.ReadingListResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	list, err := c.findOwned(tx, request.ListID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := c.ReadingListRepository.FindItems(tx, list.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to get reading list items : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	var posts []entity.Post
+	if err := tx.Unscoped().Select("ID", "Slug").Where("slug IN ?", request.Slugs).Find(&posts).Error; err != nil {
+		c.Log.Warnf("Failed to find posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	idBySlug := make(map[string]uint, len(posts))
+	for _, post := range posts {
+		idBySlug[post.Slug] = post.ID
+	}
+
+	inList := make(map[uint]bool, len(items))
+	for _, item := range items {
+		inList[item.PostID] = true
+	}
+
+	postIds := make([]uint, 0, len(request.Slugs))
+	seen := make(map[uint]bool, len(request.Slugs))
+	for _, slug := range request.Slugs {
+		id, ok := idBySlug[slug]
+		if !ok || !inList[id] || seen[id] {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Slugs must name every post of the list exactly once")
+		}
+		seen[id] = true
+		postIds = append(postIds, id)
+	}
+	if len(postIds) != len(items) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Slugs must name every post of the list exactly once")
+	}
+
+	if err := c.ReadingListRepository.SetPositions(tx, list.ID, postIds); err != nil {
+		c.Log.Warnf("Failed to reorder reading list : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return c.touchAndCommit(tx, list)
+}
+
+// findOwned locks the list for the change, lists of other users look like they do not exist.
+func (c *ReadingListUseCase) findOwned(tx *gorm.DB, listId uint, userId string) (*entity.ReadingList, error) {
+	list := new(entity.ReadingList)
+	if err := c.ReadingListRepository.FindByIdForUpdate(tx, list, listId); err != nil {
+		c.Log.Warnf("Failed to find reading list : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+	if list.UserID != userId {
+		return nil, fiber.ErrNotFound
+	}
+	return list, nil
+}
+
+func (c *ReadingListUseCase) touchAndCommit(tx *gorm.DB, list *entity.ReadingList) (*model.ReadingListResponse, error) {
+	if err := c.ReadingListRepository.Save(tx, list); err != nil {
+		c.Log.Warnf("Failed to update reading list : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response, err := c.withPosts(tx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return response, nil
+}
+
+func (c *ReadingListUseCase) withPosts(tx *gorm.DB, list *entity.ReadingList) (*model.ReadingListResponse, error) {
+	posts, err := c.PostRepository.FindByReadingListId(tx, list.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to get reading list posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := converter.ReadingListToResponse(list, int64(len(posts)))
+	response.Posts = make([]model.PostResponse, len(posts))
+	for i, post := range posts {
+		response.Posts[i] = *converter.PostToResponse(&post)
+	}
+	return response, nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"go-blog/internal/entity"
+	"go-blog/internal/gateway/realtime"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"gorm.io/gorm"
+)
+
+const (
+	EventPostCreated    = "post.created"
+	EventPostUpdated    = "post.updated"
+	EventAccountUpdated = "account.updated"
+)
+
+// publish sends an event after the transaction committed. Real-time delivery is best effort,
+// a failure is logged and never fails the action itself.
+func publish(ctx context.Context, publisher realtime.Publisher, log *logrus.Logger, topic string, eventType string, data any) {
+	if err := publisher.Publish(ctx, topic, eventType, data); err != nil {
+		log.Warnf("Failed to publish %s event : %+v", eventType, err)
+	}
+}
+
+// publishPostUpdated reloads the post outside the committed transaction so the counts sent are the committed ones.
+func publishPostUpdated(ctx context.Context, db *gorm.DB, publisher realtime.Publisher, log *logrus.Logger, postId uint, change string) {
+	post := new(entity.Post)
+	if err := db.WithContext(ctx).Preload("ReactionCounts", "count > 0").Take(post, postId).Error; err != nil {
+		log.Warnf("Failed to load post %d for its update event : %+v", postId, err)
+		return
+	}
+
+	publish(ctx, publisher, log, realtime.TopicPost(post.Slug), EventPostUpdated, &model.PostUpdatedEvent{
+		Slug:         post.Slug,
+		Change:       change,
+		CommentCount: post.CommentCount,
+		Reactions:    converter.ReactionCountsToResponse(post.ReactionCounts),
+	})
+}
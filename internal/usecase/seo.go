@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"strings"
+)
+
+// metaDescriptionLength is about what search engines show of a description.
+const metaDescriptionLength = 160
+
+// postMeta fills the page head of a post. The SEO fields fall back to the title, an excerpt of the
+// content, the post URL on APP_BASE_URL, the cover and SEO_DEFAULT_IMAGE.
+func postMeta(config *viper.Viper, post *entity.Post) *model.PostMeta {
+	baseURL := strings.TrimRight(config.GetString("APP_BASE_URL"), "/")
+
+	title := post.MetaTitle
+	if title == "" {
+		title = post.Title
+	}
+	description := post.MetaDescription
+	if description == "" {
+		description = truncate(helper.MarkdownToText(post.Content), metaDescriptionLength)
+	}
+	canonicalURL := post.CanonicalURL
+	if canonicalURL == "" {
+		canonicalURL = baseURL + helper.PostPath(post.Slug)
+	}
+	image := post.SocialImage
+	if image == "" && post.CoverMedia != nil {
+		image = post.CoverMedia.URL
+	}
+	if image == "" {
+		image = config.GetString("SEO_DEFAULT_IMAGE")
+	}
+
+	robots := "index, follow"
+	if post.NoIndex {
+		robots = "noindex, follow"
+	}
+	card := "summary"
+	if image != "" {
+		card = "summary_large_image"
+	}
+
+	var tags []string
+	for _, tag := range post.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return &model.PostMeta{
+		Title:        title,
+		Description:  description,
+		CanonicalURL: canonicalURL,
+		Image:        image,
+		Robots:       robots,
+		OpenGraph: model.OpenGraphMeta{
+			Type:          "article",
+			SiteName:      config.GetString("APP_NAME"),
+			Title:         title,
+			Description:   description,
+			URL:           canonicalURL,
+			Image:         image,
+			Author:        baseURL + helper.AuthorPath(post.User.Username),
+			Tags:          tags,
+			PublishedTime: post.PublishedAt,
+			ModifiedTime:  post.UpdatedAt,
+		},
+		Twitter: model.TwitterCardMeta{
+			Card:        card,
+			Site:        config.GetString("SEO_TWITTER_SITE"),
+			Title:       title,
+			Description: description,
+			Image:       image,
+		},
+	}
+}
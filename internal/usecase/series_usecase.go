@@ -0,0 +1,427 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+)
+
+type SeriesUseCase struct {
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	Config                    *viper.Viper
+	SeriesRepository          *repository.SeriesRepository
+	PostRepository            *repository.PostRepository
+	UserRepository            *repository.UserRepository
+	UsernameHistoryRepository *repository.UsernameHistoryRepository
+}
+
+func NewSeriesUseCase(
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, seriesRepository *repository.SeriesRepository, postRepository *repository.PostRepository, userRepository *repository.UserRepository, usernameHistoryRepository *repository.UsernameHistoryRepository,
+) *SeriesUseCase {
+	return &SeriesUseCase{
+		DB:                        db,
+		Log:                       logger,
+		Validate:                  validate,
+		Config:                    config,
+		SeriesRepository:          seriesRepository,
+		PostRepository:            postRepository,
+		UserRepository:            userRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+	}
+}
+
+// ListOwn returns every series of the logged in user, counting the parts that are not published yet.
+func (c *SeriesUseCase) ListOwn(ctx context.Context, userId string) ([]*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	return c.list(tx, userId, false)
+}
+
+// ListByUsername returns the series of an author, counting their published parts.
+func (c *SeriesUseCase) ListByUsername(ctx context.Context, username string) ([]*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByUsername(tx, user, username); err != nil {
+		c.Log.Warnf("Failed find user by username '%s' : %+v", username, err)
+		if moved := findMovedUsername(tx, c.Config, c.UsernameHistoryRepository, username); moved != nil {
+			return nil, moved
+		}
+		return nil, fiber.ErrNotFound
+	}
+
+	return c.list(tx, user.ID, true)
+}
+
+func (c *SeriesUseCase) list(tx *gorm.DB, userId string, publishedOnly bool) ([]*model.SeriesResponse, error) {
+	series, err := c.SeriesRepository.FindAllByUserId(tx, userId)
+	if err != nil {
+		c.Log.Warnf("Failed to get series : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	seriesIds := make([]uint, len(series))
+	for i, item := range series {
+		seriesIds[i] = item.ID
+	}
+	counts, err := c.SeriesRepository.CountPostsBySeriesIds(tx, seriesIds, publishedOnly)
+	if err != nil {
+		c.Log.Warnf("Failed to count series posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := make([]*model.SeriesResponse, len(series))
+	for i, item := range series {
+		response[i] = converter.SeriesToResponse(&item, counts[item.ID])
+	}
+	return response, nil
+}
+
+// Get returns a series with its published parts in order.
+func (c *SeriesUseCase) Get(ctx context.Context, slug string) (*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	series := new(entity.Series)
+	if err := c.SeriesRepository.FindBySlug(tx, series, slug); err != nil {
+		c.Log.Warnf("Failed to find series by slug '%s': %+v", slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	response, err := c.withPosts(tx, series, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return response, nil
+}
+
+// Create gives the series a slug from its title, numbered when it is already taken.
+func (c *SeriesUseCase) Create(ctx context.Context, request *model.CreateSeriesRequest) (*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Title is required")
+	}
+
+	slug, err := c.uniqueSlug(tx, title)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &entity.Series{
+		UserID:      request.UserId,
+		Title:       title,
+		Slug:        slug,
+		Description: strings.TrimSpace(request.Description),
+	}
+	if err := c.SeriesRepository.Create(tx, series); err != nil {
+		c.Log.Warnf("Failed to create series : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.UserRepository.FindById(tx, &series.User, request.UserId); err != nil {
+		c.Log.Warnf("Failed to find user : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.SeriesToResponse(series, 0), nil
+}
+
+func (c *SeriesUseCase) Update(ctx context.Context, request *model.UpdateSeriesRequest) (*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	series, err := c.findOwned(tx, request.ID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if title := strings.TrimSpace(request.Title); title != "" {
+		series.Title = title
+	}
+	if description := strings.TrimSpace(request.Description); description != "" {
+		series.Description = description
+	}
+
+	return c.touchAndCommit(tx, series)
+}
+
+// Delete removes the series, its posts stay and become standalone again.
+func (c *SeriesUseCase) Delete(ctx context.Context, seriesId uint, userId string) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	series, err := c.findOwned(tx, seriesId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := c.SeriesRepository.DeleteWithItems(tx, series); err != nil {
+		c.Log.Warnf("Failed to delete series : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+	return nil
+}
+
+// AddPost makes one of the user's own posts a part of the series, a post can only be in one series.
+func (c *SeriesUseCase) AddPost(ctx context.Context, request *model.SeriesPostRequest) (*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	series, err := c.findOwned(tx, request.SeriesID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	post := new(entity.Post)
+	if err := tx.Where("slug = ?", request.Slug).Take(post).Error; err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+	if post.UserID != request.UserId {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Only your own posts can be added to a series")
+	}
+
+	current := new(entity.Series)
+	if err := c.SeriesRepository.FindByPostId(tx, current, post.ID); err == nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "Post is already part of the series "+current.Title)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.Log.Warnf("Failed to find series of post : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.SeriesRepository.InsertItem(tx, series.ID, post.ID, request.Position); err != nil {
+		c.Log.Warnf("Failed to add post to series : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return c.touchAndCommit(tx, series)
+}
+
+func (c *SeriesUseCase) RemovePost(ctx context.Context, request *model.SeriesPostRequest) (*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	series, err := c.findOwned(tx, request.SeriesID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unscoped so a post that was deleted meanwhile can still be taken out of the series.
+	post := new(entity.Post)
+	if err := tx.Unscoped().Where("slug = ?", request.Slug).Take(post).Error; err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", request.Slug, err)
+		return nil, fiber.ErrNotFound
+	}
+
+	removed, err := c.SeriesRepository.RemoveItem(tx, series.ID, post.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to remove post from series : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if !removed {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Post is not in this series")
+	}
+
+	return c.touchAndCommit(tx, series)
+}
+
+// Reorder applies the full new order of a series, every part has to be named exactly once.
+func (c *SeriesUseCase) Reorder(ctx context.Context, request *model.ReorderSeriesRequest) (*model.SeriesResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	series, err := c.findOwned(tx, request.SeriesID, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := c.SeriesRepository.FindItems(tx, series.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to get series items : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	var posts []entity.Post
+	if err := tx.Unscoped().Select("ID", "Slug").Where("slug IN ?", request.Slugs).Find(&posts).Error; err != nil {
+		c.Log.Warnf("Failed to find posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	idBySlug := make(map[string]uint, len(posts))
+	for _, post := range posts {
+		idBySlug[post.Slug] = post.ID
+	}
+
+	inSeries := make(map[uint]bool, len(items))
+	for _, item := range items {
+		inSeries[item.PostID] = true
+	}
+
+	postIds := make([]uint, 0, len(request.Slugs))
+	seen := make(map[uint]bool, len(request.Slugs))
+	for _, slug := range request.Slugs {
+		id, ok := idBySlug[slug]
+		if !ok || !inSeries[id] || seen[id] {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Slugs must name every post of the series exactly once")
+		}
+		seen[id] = true
+		postIds = append(postIds, id)
+	}
+	if len(postIds) != len(items) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Slugs must name every post of the series exactly once")
+	}
+
+	if err := c.SeriesRepository.SetPositions(tx, series.ID, postIds); err != nil {
+		c.Log.Warnf("Failed to reorder series : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return c.touchAndCommit(tx, series)
+}
+
+// uniqueSlug numbers the slug of title from 2 on until it is free.
+func (c *SeriesUseCase) uniqueSlug(tx *gorm.DB, title string) (string, error) {
+	base := helper.GenerateSlug(title)
+	if base == "" {
+		base = "series"
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := c.SeriesRepository.ExistsBySlug(tx, slug)
+		if err != nil {
+			c.Log.Warnf("Failed to check series slug : %+v", err)
+			return "", fiber.ErrInternalServerError
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = base + "-" + strconv.Itoa(i)
+	}
+}
+
+// findOwned locks the series for the change, series of other users look like they do not exist.
+func (c *SeriesUseCase) findOwned(tx *gorm.DB, seriesId uint, userId string) (*entity.Series, error) {
+	series := new(entity.Series)
+	if err := c.SeriesRepository.FindByIdForUpdate(tx, series, seriesId); err != nil {
+		c.Log.Warnf("Failed to find series : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+	if series.UserID != userId {
+		return nil, fiber.ErrNotFound
+	}
+	return series, nil
+}
+
+func (c *SeriesUseCase) touchAndCommit(tx *gorm.DB, series *entity.Series) (*model.SeriesResponse, error) {
+	if err := c.SeriesRepository.Save(tx, series); err != nil {
+		c.Log.Warnf("Failed to update series : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response, err := c.withPosts(tx, series, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return response, nil
+}
+
+func (c *SeriesUseCase) withPosts(tx *gorm.DB, series *entity.Series, publishedOnly bool) (*model.SeriesResponse, error) {
+	posts, err := c.PostRepository.FindBySeriesId(tx, series.ID, publishedOnly)
+	if err != nil {
+		c.Log.Warnf("Failed to get series posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := converter.SeriesToResponse(series, int64(len(posts)))
+	response.Posts = make([]model.PostResponse, len(posts))
+	for i, post := range posts {
+		response.Posts[i] = *converter.PostToResponse(&post)
+	}
+	return response, nil
+}
+
+// findPostSeries places a post in its series with links to the previous and next published parts,
+// it returns nil for a post that is not part of a series.
+func findPostSeries(tx *gorm.DB, seriesRepository *repository.SeriesRepository, postRepository *repository.PostRepository, postId uint) (*model.PostSeriesResponse, error) {
+	series := new(entity.Series)
+	if err := seriesRepository.FindByPostId(tx, series, postId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	parts, err := postRepository.FindSeriesParts(tx, series.ID)
+	if err != nil {
+		return nil, err
+	}
+	return converter.SeriesToPostResponse(series, parts, postId), nil
+}
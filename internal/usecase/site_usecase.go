@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"html/template"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// summaryLength is the length of the excerpts on list pages.
+const summaryLength = 240
+
+// SiteLayout is the theme layout every page is rendered in.
+const SiteLayout = "layouts/main"
+
+// SiteUseCase builds the pages of the server-rendered blog, only published posts are shown.
+type SiteUseCase struct {
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Config                    *viper.Viper
+	PostRepository            *repository.PostRepository
+	TagRepository             *repository.TagRepository
+	UserRepository            *repository.UserRepository
+	UsernameHistoryRepository *repository.UsernameHistoryRepository
+	SeriesRepository          *repository.SeriesRepository
+}
+
+func NewSiteUseCase(
+	db *gorm.DB, logger *logrus.Logger, config *viper.Viper, postRepository *repository.PostRepository, tagRepository *repository.TagRepository, userRepository *repository.UserRepository, usernameHistoryRepository *repository.UsernameHistoryRepository, seriesRepository *repository.SeriesRepository,
+) *SiteUseCase {
+	return &SiteUseCase{
+		DB:                        db,
+		Log:                       logger,
+		Config:                    config,
+		PostRepository:            postRepository,
+		TagRepository:             tagRepository,
+		UserRepository:            userRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+		SeriesRepository:          seriesRepository,
+	}
+}
+
+func (c *SiteUseCase) Home(ctx context.Context, page int) (*model.PostListPage, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	result := &model.PostListPage{SitePage: c.sitePage("", "")}
+	pageURL := func(page int) string { return helper.PagePath("/", page) }
+	if err := c.listPosts(tx, &model.SearchPostRequest{}, page, pageURL, result); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return result, nil
+}
+
+func (c *SiteUseCase) Post(ctx context.Context, slug string) (*model.PostPage, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	post := new(entity.Post)
+	if err := c.PostRepository.FindBySlug(tx, post, slug); err != nil {
+		c.Log.Warnf("Failed to find post by slug '%s': %+v", slug, err)
+		return nil, fiber.ErrNotFound
+	}
+	if post.PublishedAt == nil || post.PublishedAt.After(time.Now()) {
+		return nil, fiber.ErrNotFound
+	}
+
+	series, err := findPostSeries(tx, c.SeriesRepository, c.PostRepository, post.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to find series of post : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	meta := postMeta(c.Config, post)
+	result := &model.PostPage{
+		SitePage: c.sitePage(meta.Title, meta.Description),
+		Post:     c.summarize(post),
+		Content:  template.HTML(helper.RenderMarkdown(post.Content)),
+		Gallery:  converter.PostToResponse(post).Gallery,
+		Series:   series,
+	}
+	result.CanonicalURL = meta.CanonicalURL
+	result.Robots = meta.Robots
+	result.Meta = meta
+	return result, nil
+}
+
+func (c *SiteUseCase) Tag(ctx context.Context, slug string, page int) (*model.PostListPage, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	tag := new(entity.Tag)
+	if err := c.TagRepository.FindBySlug(tx, tag, slug); err != nil {
+		c.Log.Warnf("Failed find tag by slug : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	result := &model.PostListPage{
+		SitePage: c.sitePage("#"+tag.Name, "Posts tagged "+tag.Name),
+		Heading:  "#" + tag.Name,
+	}
+	result.FeedURL = c.baseURL() + "/tags/" + url.PathEscape(tag.Slug) + "/feed.xml"
+	pageURL := func(page int) string { return helper.PagePath(helper.TagPath(tag.Slug), page) }
+	if err := c.listPosts(tx, &model.SearchPostRequest{Tags: []string{tag.Slug}}, page, pageURL, result); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return result, nil
+}
+
+// Author returns a *UsernameMovedError for a recently renamed author so the caller can redirect.
+func (c *SiteUseCase) Author(ctx context.Context, username string, page int) (*model.PostListPage, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByUsername(tx, user, username); err != nil {
+		if moved := findMovedUsername(tx, c.Config, c.UsernameHistoryRepository, username); moved != nil {
+			return nil, moved
+		}
+		c.Log.Warnf("Failed find user by username : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	result := &model.PostListPage{
+		SitePage: c.sitePage(user.Name, user.Bio),
+		Heading:  user.Name,
+		Intro:    user.Bio,
+	}
+	result.FeedURL = c.baseURL() + "/posts/" + url.PathEscape(user.Username) + "/feed.xml"
+	pageURL := func(page int) string { return helper.PagePath(helper.AuthorPath(user.Username), page) }
+	if err := c.listPosts(tx, &model.SearchPostRequest{Username: user.Username}, page, pageURL, result); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return result, nil
+}
+
+// Series lists the published parts of a series in order, a series without any is not found.
+func (c *SiteUseCase) Series(ctx context.Context, slug string) (*model.SeriesPage, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	series := new(entity.Series)
+	if err := c.SeriesRepository.FindBySlug(tx, series, slug); err != nil {
+		c.Log.Warnf("Failed find series by slug : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	posts, err := c.PostRepository.FindBySeriesId(tx, series.ID, true)
+	if err != nil {
+		c.Log.Warnf("Failed to get series posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if len(posts) == 0 {
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	result := &model.SeriesPage{
+		SitePage: c.sitePage(series.Title, series.Description),
+		Heading:  series.Title,
+		Intro:    series.Description,
+		Author:   model.LinkView{Name: series.User.Name, URL: helper.AuthorPath(series.User.Username)},
+		Posts:    make([]model.PostSummary, len(posts)),
+	}
+	result.CanonicalURL = c.baseURL() + helper.SeriesPath(series.Slug)
+	result.FeedURL = c.baseURL() + helper.SeriesPath(series.Slug) + "/feed.xml"
+	for i := range posts {
+		result.Posts[i] = c.summarize(&posts[i])
+	}
+	return result, nil
+}
+
+// Search looks the query up in the post titles, the result pages are not indexed.
+func (c *SiteUseCase) Search(ctx context.Context, query string, page int) (*model.PostListPage, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	query = strings.TrimSpace(query)
+	result := &model.PostListPage{
+		SitePage: c.sitePage("Search", ""),
+		Heading:  "Search",
+		Query:    query,
+	}
+	result.Robots = "noindex, follow"
+	if query == "" {
+		return result, nil
+	}
+
+	pageURL := func(page int) string {
+		return "/search?q=" + url.QueryEscape(query) + "&page=" + strconv.Itoa(page)
+	}
+	if err := c.listPosts(tx, &model.SearchPostRequest{Title: query}, page, pageURL, result); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	return result, nil
+}
+
+// Archive lists every published post grouped by month, newest first.
+func (c *SiteUseCase) Archive(ctx context.Context) (*model.ArchivePage, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	posts, err := c.PostRepository.FindArchive(tx)
+	if err != nil {
+		c.Log.Warnf("Failed to get archive : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	result := &model.ArchivePage{SitePage: c.sitePage("Archive", "")}
+	result.CanonicalURL = c.baseURL() + "/archive"
+	for _, post := range posts {
+		label := post.PublishedAt.Format("January 2006")
+		if len(result.Months) == 0 || result.Months[len(result.Months)-1].Label != label {
+			result.Months = append(result.Months, model.ArchiveMonth{Label: label})
+		}
+		month := &result.Months[len(result.Months)-1]
+		month.Posts = append(month.Posts, model.PostSummary{
+			Title:       post.Title,
+			URL:         helper.PostPath(post.Slug),
+			PublishedAt: post.PublishedAt,
+		})
+	}
+	return result, nil
+}
+
+func (c *SiteUseCase) ErrorPage(code int, message string) *model.ErrorPage {
+	result := &model.ErrorPage{
+		SitePage: c.sitePage(message, ""),
+		Code:     code,
+		Message:  message,
+	}
+	result.Robots = "noindex, follow"
+	return result
+}
+
+// listPosts fills result with a page of the published posts matching search, pages past the last one are not found.
+func (c *SiteUseCase) listPosts(tx *gorm.DB, search *model.SearchPostRequest, page int, pageURL func(page int) string, result *model.PostListPage) error {
+	if page < 1 {
+		return fiber.ErrNotFound
+	}
+
+	search.Sort = "published"
+	search.Published = true
+	search.Paginate = model.Pagination{Page: page, Size: c.configInt("SITE_PAGE_SIZE", 10)}
+
+	posts, total, err := c.PostRepository.Find(tx, search)
+	if err != nil {
+		c.Log.Warnf("Failed to get posts : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	totalPage := int(math.Ceil(float64(total) / float64(search.Paginate.Size)))
+	if page > max(totalPage, 1) {
+		return fiber.ErrNotFound
+	}
+
+	result.Posts = make([]model.PostSummary, len(posts))
+	for i := range posts {
+		result.Posts[i] = c.summarize(&posts[i])
+	}
+
+	result.Pagination = model.PageLinks{Page: page, TotalPage: totalPage}
+	if page > 1 {
+		result.Pagination.PrevURL = pageURL(page - 1)
+	}
+	if page < totalPage {
+		result.Pagination.NextURL = pageURL(page + 1)
+	}
+	if result.CanonicalURL == "" {
+		result.CanonicalURL = c.baseURL() + pageURL(page)
+	}
+	return nil
+}
+
+func (c *SiteUseCase) summarize(post *entity.Post) model.PostSummary {
+	summary := model.PostSummary{
+		Title:        post.Title,
+		URL:          helper.PostPath(post.Slug),
+		Excerpt:      truncate(helper.MarkdownToText(post.Content), summaryLength),
+		Author:       model.LinkView{Name: post.User.Name, URL: helper.AuthorPath(post.User.Username)},
+		CommentCount: post.CommentCount,
+		PublishedAt:  post.PublishedAt,
+		UpdatedAt:    post.UpdatedAt,
+	}
+	if post.CoverMedia != nil {
+		summary.Cover = converter.PostCoverToResponse(post)
+	}
+	for _, tag := range post.Tags {
+		summary.Tags = append(summary.Tags, model.LinkView{Name: tag.Name, URL: helper.TagPath(tag.Slug)})
+	}
+	for _, author := range converter.PostAuthorsToResponse(post)[1:] {
+		link := model.LinkView{Name: author.Name, URL: helper.AuthorPath(author.Username)}
+		switch author.Role {
+		case entity.PostAuthorRoleAuthor:
+			summary.CoAuthors = append(summary.CoAuthors, link)
+		case entity.PostAuthorRoleEditor:
+			summary.Credits = append(summary.Credits, model.CreditView{Label: "Edited by", Name: link.Name, URL: link.URL})
+		case entity.PostAuthorRoleReviewer:
+			summary.Credits = append(summary.Credits, model.CreditView{Label: "Reviewed by", Name: link.Name, URL: link.URL})
+		}
+	}
+	return summary
+}
+
+// sitePage is the head of a page titled title, the home page passes an empty one and gets the site name alone.
+func (c *SiteUseCase) sitePage(title string, description string) model.SitePage {
+	siteName := c.Config.GetString("APP_NAME")
+	if title == "" {
+		title = siteName
+	} else {
+		title += " - " + siteName
+	}
+	if description == "" {
+		description = c.Config.GetString("SITE_DESCRIPTION")
+	}
+	return model.SitePage{
+		SiteName:    siteName,
+		Title:       title,
+		Description: description,
+		Robots:      "index, follow",
+		FeedURL:     c.baseURL() + "/feed.xml",
+	}
+}
+
+func (c *SiteUseCase) baseURL() string {
+	return strings.TrimRight(c.Config.GetString("APP_BASE_URL"), "/")
+}
+
+func (c *SiteUseCase) configInt(key string, fallback int) int {
+	if value := c.Config.GetInt(key); value > 0 {
+		return value
+	}
+	return fallback
+}
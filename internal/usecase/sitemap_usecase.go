@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sitemapMaxURLs is the limit of the sitemap protocol for a single file.
+const sitemapMaxURLs = 50000
+
+const (
+	SitemapPosts   = "posts"
+	SitemapTags    = "tags"
+	SitemapAuthors = "authors"
+	SitemapSeries  = "series"
+)
+
+type SitemapUseCase struct {
+	DB               *gorm.DB
+	Log              *logrus.Logger
+	Config           *viper.Viper
+	PostRepository   *repository.PostRepository
+	TagRepository    *repository.TagRepository
+	UserRepository   *repository.UserRepository
+	SeriesRepository *repository.SeriesRepository
+
+	mu    sync.Mutex
+	cache *sitemap
+}
+
+// sitemap is a generated index with its pages, kept until the content version changes.
+type sitemap struct {
+	version string
+	index   *model.SitemapIndex
+	pages   map[string][]*model.SitemapURLSet
+}
+
+func NewSitemapUseCase(
+	db *gorm.DB, logger *logrus.Logger, config *viper.Viper, postRepository *repository.PostRepository, tagRepository *repository.TagRepository, userRepository *repository.UserRepository, seriesRepository *repository.SeriesRepository,
+) *SitemapUseCase {
+	return &SitemapUseCase{
+		DB:               db,
+		Log:              logger,
+		Config:           config,
+		PostRepository:   postRepository,
+		TagRepository:    tagRepository,
+		UserRepository:   userRepository,
+		SeriesRepository: seriesRepository,
+	}
+}
+
+// Index returns the sitemap index, it lists every page of posts, tags, authors and series.
+func (c *SitemapUseCase) Index(ctx context.Context) (*model.SitemapIndex, error) {
+	current, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return current.index, nil
+}
+
+// Page returns a page of a section, pages start at 1.
+func (c *SitemapUseCase) Page(ctx context.Context, section string, page int) (*model.SitemapURLSet, error) {
+	current, err := c.current(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := current.pages[section]
+	if page < 1 || page > len(pages) {
+		return nil, fiber.ErrNotFound
+	}
+	return pages[page-1], nil
+}
+
+// Robots returns robots.txt, ROBOTS_DISALLOW_ALL keeps crawlers off a whole instance such as a staging one.
+func (c *SitemapUseCase) Robots() string {
+	var robots strings.Builder
+	robots.WriteString("User-agent: *\n")
+	if c.Config.GetBool("ROBOTS_DISALLOW_ALL") {
+		robots.WriteString("Disallow: /\n")
+	} else {
+		for _, path := range strings.Split(c.Config.GetString("ROBOTS_DISALLOW"), ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				robots.WriteString("Disallow: " + path + "\n")
+			}
+		}
+	}
+	robots.WriteString("\nSitemap: " + c.baseURL() + "/sitemap.xml\n")
+	return robots.String()
+}
+
+// current rebuilds the sitemap when the content version moved. The lock is held while building so
+// concurrent requests after a change wait for one build instead of each running their own.
+func (c *SitemapUseCase) current(ctx context.Context) (*sitemap, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	version, err := c.PostRepository.SitemapVersion(tx)
+	if err != nil {
+		c.Log.Warnf("Failed to get sitemap version : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache != nil && c.cache.version == version {
+		return c.cache, nil
+	}
+
+	posts, err := c.PostRepository.FindSitemapEntries(tx)
+	if err != nil {
+		c.Log.Warnf("Failed to get sitemap posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	tags, err := c.TagRepository.FindSitemapEntries(tx)
+	if err != nil {
+		c.Log.Warnf("Failed to get sitemap tags : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	authors, err := c.UserRepository.FindSitemapEntries(tx)
+	if err != nil {
+		c.Log.Warnf("Failed to get sitemap authors : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+	series, err := c.SeriesRepository.FindSitemapEntries(tx)
+	if err != nil {
+		c.Log.Warnf("Failed to get sitemap series : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	baseURL := c.baseURL()
+	built := &sitemap{
+		version: version,
+		index:   &model.SitemapIndex{},
+		pages:   make(map[string][]*model.SitemapURLSet),
+	}
+	sections := []struct {
+		name    string
+		entries []model.SitemapEntry
+		loc     func(name string) string
+	}{
+		{SitemapPosts, posts, func(name string) string { return baseURL + helper.PostPath(name) }},
+		{SitemapTags, tags, func(name string) string { return baseURL + helper.TagPath(name) }},
+		{SitemapAuthors, authors, func(name string) string { return baseURL + helper.AuthorPath(name) }},
+		{SitemapSeries, series, func(name string) string { return baseURL + helper.SeriesPath(name) }},
+	}
+
+	pageSize := min(c.configInt("SITEMAP_PAGE_SIZE", sitemapMaxURLs), sitemapMaxURLs)
+	for _, section := range sections {
+		for start := 0; start < len(section.entries); start += pageSize {
+			page := &model.SitemapURLSet{}
+			var lastMod *time.Time
+			for _, entry := range section.entries[start:min(start+pageSize, len(section.entries))] {
+				page.URLs = append(page.URLs, model.SitemapLocation{Loc: section.loc(entry.Name), LastMod: sitemapDate(entry.LastMod)})
+				if entry.LastMod != nil && (lastMod == nil || entry.LastMod.After(*lastMod)) {
+					lastMod = entry.LastMod
+				}
+			}
+
+			built.pages[section.name] = append(built.pages[section.name], page)
+			built.index.Sitemaps = append(built.index.Sitemaps, model.SitemapLocation{
+				Loc:     fmt.Sprintf("%s/sitemaps/%s/%d.xml", baseURL, section.name, len(built.pages[section.name])),
+				LastMod: sitemapDate(lastMod),
+			})
+		}
+	}
+
+	c.cache = built
+	return built, nil
+}
+
+func (c *SitemapUseCase) baseURL() string {
+	return strings.TrimRight(c.Config.GetString("APP_BASE_URL"), "/")
+}
+
+func (c *SitemapUseCase) configInt(key string, fallback int) int {
+	if value := c.Config.GetInt(key); value > 0 {
+		return value
+	}
+	return fallback
+}
+
+func sitemapDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,420 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"go-blog/internal/model/converter"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// staticManifest lists the hash of every exported file, the next export skips the files whose hash did not change.
+const staticManifest = ".static-manifest.json"
+
+// StaticSiteUseCase exports the server-rendered blog to static files for a read-only mirror. Pages are
+// written as <path>/index.html so the URLs of the live site keep working on a plain file host.
+type StaticSiteUseCase struct {
+	DB               *gorm.DB
+	Log              *logrus.Logger
+	Config           *viper.Viper
+	PostRepository   *repository.PostRepository
+	TagRepository    *repository.TagRepository
+	UserRepository   *repository.UserRepository
+	SeriesRepository *repository.SeriesRepository
+	Site             *SiteUseCase
+	Syndication      *SyndicationUseCase
+	Sitemap          *SitemapUseCase
+	Views            fiber.Views
+	Theme            fs.FS
+}
+
+func NewStaticSiteUseCase(
+	db *gorm.DB, logger *logrus.Logger, config *viper.Viper, postRepository *repository.PostRepository, tagRepository *repository.TagRepository, userRepository *repository.UserRepository, seriesRepository *repository.SeriesRepository, site *SiteUseCase, syndication *SyndicationUseCase, sitemap *SitemapUseCase, views fiber.Views, theme fs.FS,
+) *StaticSiteUseCase {
+	return &StaticSiteUseCase{
+		DB:               db,
+		Log:              logger,
+		Config:           config,
+		PostRepository:   postRepository,
+		TagRepository:    tagRepository,
+		UserRepository:   userRepository,
+		SeriesRepository: seriesRepository,
+		Site:             site,
+		Syndication:      syndication,
+		Sitemap:          sitemap,
+		Views:            views,
+		Theme:            theme,
+	}
+}
+
+// Export writes every published post, the home, tag and author pages with their pagination, the series, the archive,
+// the feeds, the sitemap and the theme assets to dir. Files left from a previous export that are no longer
+// part of the site are removed, search is left out as it needs the server.
+func (c *StaticSiteUseCase) Export(ctx context.Context, dir string) (*model.StaticExportResult, error) {
+	writer, err := newStaticWriter(dir)
+	if err != nil {
+		c.Log.Warnf("Failed to read static manifest : %+v", err)
+		return nil, err
+	}
+
+	steps := []func(ctx context.Context, writer *staticWriter) error{
+		c.exportPages,
+		c.exportFeeds,
+		c.exportSitemap,
+		c.exportAssets,
+	}
+	for _, step := range steps {
+		if err := step(ctx, writer); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.finish(); err != nil {
+		c.Log.Warnf("Failed to finish static export : %+v", err)
+		return nil, err
+	}
+	return writer.result, nil
+}
+
+func (c *StaticSiteUseCase) exportPages(ctx context.Context, writer *staticWriter) error {
+	db := c.DB.WithContext(ctx)
+
+	if err := c.exportList(writer, "home", "/", func(page int) (*model.PostListPage, error) {
+		return c.Site.Home(ctx, page)
+	}); err != nil {
+		return err
+	}
+
+	posts, err := c.PostRepository.FindArchive(db)
+	if err != nil {
+		c.Log.Warnf("Failed to get posts : %+v", err)
+		return err
+	}
+	for _, post := range posts {
+		page, err := c.Site.Post(ctx, post.Slug)
+		if err != nil {
+			return fmt.Errorf("post %s: %w", post.Slug, err)
+		}
+		if err := c.render(writer, helper.PostPath(post.Slug), "post", page); err != nil {
+			return err
+		}
+	}
+
+	tags, err := c.TagRepository.FindSitemapEntries(db)
+	if err != nil {
+		c.Log.Warnf("Failed to get tags : %+v", err)
+		return err
+	}
+	for _, tag := range tags {
+		if err := c.exportList(writer, "list", helper.TagPath(tag.Name), func(page int) (*model.PostListPage, error) {
+			return c.Site.Tag(ctx, tag.Name, page)
+		}); err != nil {
+			return err
+		}
+	}
+
+	authors, err := c.UserRepository.FindSitemapEntries(db)
+	if err != nil {
+		c.Log.Warnf("Failed to get authors : %+v", err)
+		return err
+	}
+	for _, author := range authors {
+		if err := c.exportList(writer, "list", helper.AuthorPath(author.Name), func(page int) (*model.PostListPage, error) {
+			return c.Site.Author(ctx, author.Name, page)
+		}); err != nil {
+			return err
+		}
+	}
+
+	series, err := c.SeriesRepository.FindSitemapEntries(db)
+	if err != nil {
+		c.Log.Warnf("Failed to get series : %+v", err)
+		return err
+	}
+	for _, item := range series {
+		page, err := c.Site.Series(ctx, item.Name)
+		if err != nil {
+			return fmt.Errorf("series %s: %w", item.Name, err)
+		}
+		if err := c.render(writer, helper.SeriesPath(item.Name), "series", page); err != nil {
+			return err
+		}
+	}
+
+	archive, err := c.Site.Archive(ctx)
+	if err != nil {
+		return fmt.Errorf("archive: %w", err)
+	}
+	if err := c.render(writer, "/archive", "archive", archive); err != nil {
+		return err
+	}
+
+	// Most static hosts answer unknown paths with /404.html.
+	var body bytes.Buffer
+	if err := c.Views.Render(&body, "error", c.Site.ErrorPage(fiber.StatusNotFound, "Not Found"), SiteLayout); err != nil {
+		return fmt.Errorf("render 404: %w", err)
+	}
+	return writer.write("404.html", body.Bytes())
+}
+
+// exportList renders every page of a paginated list, path is the path of its first page.
+func (c *StaticSiteUseCase) exportList(writer *staticWriter, template string, path string, load func(page int) (*model.PostListPage, error)) error {
+	for page := 1; ; page++ {
+		result, err := load(page)
+		if err != nil {
+			return fmt.Errorf("%s page %d: %w", path, page, err)
+		}
+		if err := c.render(writer, helper.PagePath(path, page), template, result); err != nil {
+			return err
+		}
+		if result.Pagination.NextURL == "" {
+			return nil
+		}
+	}
+}
+
+func (c *StaticSiteUseCase) render(writer *staticWriter, path string, template string, page any) error {
+	var body bytes.Buffer
+	if err := c.Views.Render(&body, template, page, SiteLayout); err != nil {
+		return fmt.Errorf("render %s: %w", path, err)
+	}
+
+	name, err := url.PathUnescape(strings.Trim(path, "/"))
+	if err != nil {
+		return err
+	}
+	return writer.write(strings.TrimPrefix(name+"/index.html", "/"), body.Bytes())
+}
+
+// exportFeeds writes the feeds of the site, of every author, tag and series where the live site serves them.
+func (c *StaticSiteUseCase) exportFeeds(ctx context.Context, writer *staticWriter) error {
+	db := c.DB.WithContext(ctx)
+	requests := []*model.SyndicationRequest{{}}
+
+	authors, err := c.UserRepository.FindSitemapEntries(db)
+	if err != nil {
+		c.Log.Warnf("Failed to get authors : %+v", err)
+		return err
+	}
+	for _, author := range authors {
+		requests = append(requests, &model.SyndicationRequest{Username: author.Name})
+	}
+	tags, err := c.TagRepository.FindSitemapEntries(db)
+	if err != nil {
+		c.Log.Warnf("Failed to get tags : %+v", err)
+		return err
+	}
+	for _, tag := range tags {
+		requests = append(requests, &model.SyndicationRequest{Tag: tag.Name})
+	}
+	series, err := c.SeriesRepository.FindSitemapEntries(db)
+	if err != nil {
+		c.Log.Warnf("Failed to get series : %+v", err)
+		return err
+	}
+	for _, item := range series {
+		requests = append(requests, &model.SyndicationRequest{Series: item.Name})
+	}
+
+	baseURL := strings.TrimRight(c.Config.GetString("APP_BASE_URL"), "/")
+	for _, request := range requests {
+		feed, err := c.Syndication.Build(ctx, request)
+		if err != nil {
+			return fmt.Errorf("feed %+v: %w", *request, err)
+		}
+		dir := strings.TrimPrefix(strings.TrimPrefix(feed.SelfURL, baseURL), "/")
+		if dir != "" {
+			dir += "/"
+		}
+
+		rss, err := marshalXML(converter.SyndicationToRSS(feed))
+		if err != nil {
+			return err
+		}
+		atom, err := marshalXML(converter.SyndicationToAtom(feed))
+		if err != nil {
+			return err
+		}
+		jsonFeed, err := json.Marshal(converter.SyndicationToJSONFeed(feed))
+		if err != nil {
+			return err
+		}
+
+		files := map[string][]byte{
+			converter.RSSPath:      rss,
+			converter.AtomPath:     atom,
+			converter.JSONFeedPath: jsonFeed,
+		}
+		for path, body := range files {
+			if err := writer.write(dir+strings.TrimPrefix(path, "/"), body); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *StaticSiteUseCase) exportSitemap(ctx context.Context, writer *staticWriter) error {
+	index, err := c.Sitemap.Index(ctx)
+	if err != nil {
+		return fmt.Errorf("sitemap: %w", err)
+	}
+	body, err := marshalXML(index)
+	if err != nil {
+		return err
+	}
+	if err := writer.write("sitemap.xml", body); err != nil {
+		return err
+	}
+
+	for _, section := range []string{SitemapPosts, SitemapTags, SitemapAuthors, SitemapSeries} {
+		for page := 1; ; page++ {
+			urlSet, err := c.Sitemap.Page(ctx, section, page)
+			if errors.Is(err, fiber.ErrNotFound) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("sitemap %s page %d: %w", section, page, err)
+			}
+			body, err := marshalXML(urlSet)
+			if err != nil {
+				return err
+			}
+			if err := writer.write(fmt.Sprintf("sitemaps/%s/%d.xml", section, page), body); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writer.write("robots.txt", []byte(c.Sitemap.Robots()))
+}
+
+func (c *StaticSiteUseCase) exportAssets(_ context.Context, writer *staticWriter) error {
+	return fs.WalkDir(c.Theme, "assets", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		body, err := fs.ReadFile(c.Theme, path)
+		if err != nil {
+			return err
+		}
+		return writer.write(path, body)
+	})
+}
+
+func marshalXML(document any) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// staticWriter writes the files of one export. A file is only rewritten when its content hash differs
+// from the one recorded by the previous export, and finish removes what the previous export wrote
+// but this one did not.
+type staticWriter struct {
+	dir      string
+	previous map[string]string
+	current  map[string]string
+	result   *model.StaticExportResult
+}
+
+func newStaticWriter(dir string) (*staticWriter, error) {
+	writer := &staticWriter{
+		dir:      dir,
+		previous: make(map[string]string),
+		current:  make(map[string]string),
+		result:   &model.StaticExportResult{},
+	}
+
+	manifest, err := os.ReadFile(filepath.Join(dir, staticManifest))
+	if errors.Is(err, fs.ErrNotExist) {
+		return writer, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(manifest, &writer.previous); err != nil {
+		return nil, fmt.Errorf("%s: %w", staticManifest, err)
+	}
+	return writer, nil
+}
+
+func (w *staticWriter) write(path string, body []byte) error {
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	w.current[path] = hash
+
+	target := filepath.Join(w.dir, filepath.FromSlash(path))
+	if w.previous[path] == hash {
+		if _, err := os.Stat(target); err == nil {
+			w.result.Unchanged++
+			return nil
+		}
+	}
+
+	if err := writeFileAtomic(target, body); err != nil {
+		return err
+	}
+	w.result.Written++
+	return nil
+}
+
+func (w *staticWriter) finish() error {
+	for path := range w.previous {
+		if _, ok := w.current[path]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(w.dir, filepath.FromSlash(path))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		w.result.Removed++
+	}
+
+	manifest, err := json.MarshalIndent(w.current, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(w.dir, staticManifest), manifest)
+}
+
+// writeFileAtomic renames a temporary file over target, so a host serving dir never sees half a file.
+func writeFileAtomic(target string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(body); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), target)
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/helper"
+	"go-blog/internal/model"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"net/url"
+	"strings"
+)
+
+type SyndicationUseCase struct {
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Config                    *viper.Viper
+	PostRepository            *repository.PostRepository
+	UserRepository            *repository.UserRepository
+	TagRepository             *repository.TagRepository
+	SeriesRepository          *repository.SeriesRepository
+	UsernameHistoryRepository *repository.UsernameHistoryRepository
+}
+
+func NewSyndicationUseCase(
+	db *gorm.DB, logger *logrus.Logger, config *viper.Viper, postRepository *repository.PostRepository, userRepository *repository.UserRepository, tagRepository *repository.TagRepository, seriesRepository *repository.SeriesRepository, usernameHistoryRepository *repository.UsernameHistoryRepository,
+) *SyndicationUseCase {
+	return &SyndicationUseCase{
+		DB:                        db,
+		Log:                       logger,
+		Config:                    config,
+		PostRepository:            postRepository,
+		UserRepository:            userRepository,
+		TagRepository:             tagRepository,
+		SeriesRepository:          seriesRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+	}
+}
+
+// Build returns the latest FEED_SIZE published posts of the site, an author, a tag or a series. Every URL is
+// absolute on APP_BASE_URL, and the items carry the rendered post unless FEED_CONTENT is excerpt.
+func (c *SyndicationUseCase) Build(ctx context.Context, request *model.SyndicationRequest) (*model.SyndicationFeed, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	baseURL := strings.TrimRight(c.Config.GetString("APP_BASE_URL"), "/")
+	siteName := c.Config.GetString("APP_NAME")
+
+	feed := &model.SyndicationFeed{
+		Title:       siteName,
+		Description: c.Config.GetString("SITE_DESCRIPTION"),
+		HomeURL:     baseURL + "/",
+		SelfURL:     baseURL,
+	}
+	search := &model.SearchPostRequest{
+		Sort:      "published",
+		Published: true,
+		Paginate: model.Pagination{
+			Page: 1,
+			Size: c.configInt("FEED_SIZE", 20),
+		},
+	}
+
+	switch {
+	case request.Username != "":
+		user := new(entity.User)
+		if err := c.UserRepository.FindByUsername(tx, user, request.Username); err != nil {
+			if moved := findMovedUsername(tx, c.Config, c.UsernameHistoryRepository, request.Username); moved != nil {
+				return nil, moved
+			}
+			c.Log.Warnf("Failed find user by username : %+v", err)
+			return nil, fiber.ErrNotFound
+		}
+		search.Username = user.Username
+		feed.Title = fmt.Sprintf("%s - %s", user.Name, siteName)
+		feed.HomeURL = baseURL + helper.AuthorPath(user.Username)
+		feed.SelfURL = baseURL + "/posts/" + url.PathEscape(user.Username)
+	case request.Tag != "":
+		tag := new(entity.Tag)
+		if err := c.TagRepository.FindBySlug(tx, tag, request.Tag); err != nil {
+			c.Log.Warnf("Failed find tag by slug : %+v", err)
+			return nil, fiber.ErrNotFound
+		}
+		search.Tags = []string{tag.Slug}
+		feed.Title = fmt.Sprintf("#%s - %s", tag.Name, siteName)
+		feed.HomeURL = baseURL + helper.TagPath(tag.Slug)
+		feed.SelfURL = baseURL + "/tags/" + url.PathEscape(tag.Slug)
+	case request.Series != "":
+		series := new(entity.Series)
+		if err := c.SeriesRepository.FindBySlug(tx, series, request.Series); err != nil {
+			c.Log.Warnf("Failed find series by slug : %+v", err)
+			return nil, fiber.ErrNotFound
+		}
+		search.SeriesId = series.ID
+		feed.Title = fmt.Sprintf("%s - %s", series.Title, siteName)
+		if series.Description != "" {
+			feed.Description = series.Description
+		}
+		feed.HomeURL = baseURL + helper.SeriesPath(series.Slug)
+		feed.SelfURL = feed.HomeURL
+	}
+
+	posts, _, err := c.PostRepository.Find(tx, search)
+	if err != nil {
+		c.Log.Warnf("Failed to get posts : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	full := c.Config.GetString("FEED_CONTENT") != "excerpt"
+	excerptLength := c.configInt("FEED_EXCERPT_LENGTH", 300)
+
+	feed.Items = make([]model.SyndicationItem, len(posts))
+	for i, post := range posts {
+		item := model.SyndicationItem{
+			URL:         baseURL + helper.PostPath(post.Slug),
+			Title:       post.Title,
+			Summary:     truncate(helper.MarkdownToText(post.Content), excerptLength),
+			AuthorName:  post.User.Name,
+			AuthorURL:   baseURL + helper.AuthorPath(post.User.Username),
+			PublishedAt: *post.PublishedAt,
+			UpdatedAt:   *post.UpdatedAt,
+		}
+		if full {
+			item.ContentHTML = helper.RenderMarkdown(post.Content)
+		}
+		for _, tag := range post.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		feed.Items[i] = item
+
+		if feed.Updated == nil || item.UpdatedAt.After(*feed.Updated) {
+			feed.Updated = &feed.Items[i].UpdatedAt
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return feed, nil
+}
+
+func (c *SyndicationUseCase) configInt(key string, fallback int) int {
+	if value := c.Config.GetInt(key); value > 0 {
+		return value
+	}
+	return fallback
+}
@@ -2,36 +2,91 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 	"go-blog/internal/delivery/http/middleware"
 	"go-blog/internal/entity"
+	"go-blog/internal/gateway/mail"
+	"go-blog/internal/gateway/realtime"
+	"go-blog/internal/gateway/storage"
+	"go-blog/internal/helper"
 	"go-blog/internal/model"
 	"go-blog/internal/model/converter"
 	"go-blog/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
+	"strings"
+	"time"
 )
 
 type UserUseCase struct {
-	DB             *gorm.DB
-	Log            *logrus.Logger
-	Validate       *validator.Validate
-	UserRepository *repository.UserRepository
-	Middleware     *middleware.Middleware
+	DB                        *gorm.DB
+	Log                       *logrus.Logger
+	Validate                  *validator.Validate
+	Config                    *viper.Viper
+	UserRepository            *repository.UserRepository
+	PostRepository            *repository.PostRepository
+	CommentRepository         *repository.CommentRepository
+	ReactionRepository        *repository.ReactionRepository
+	BookmarkRepository        *repository.BookmarkRepository
+	ReadingListRepository     *repository.ReadingListRepository
+	SeriesRepository          *repository.SeriesRepository
+	PostAuthorRepository      *repository.PostAuthorRepository
+	FollowRepository          *repository.FollowRepository
+	EmailChangeRepository     *repository.EmailChangeRepository
+	UsernameHistoryRepository *repository.UsernameHistoryRepository
+	AccountDeletionRepository *repository.AccountDeletionRepository
+	MediaRepository           *repository.MediaRepository
+	Storage                   storage.Storage
+	Middleware                *middleware.Middleware
+	LoginThrottle             *LoginThrottleUseCase
+	Notification              *NotificationUseCase
+	Publisher                 realtime.Publisher
+	PasswordPolicy            *helper.PasswordPolicy
+	Mailer                    mail.Mailer
+	dummyPassword             []byte
 }
 
 func NewUserUseCase(
-	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, userRepository *repository.UserRepository, mddlwr *middleware.Middleware,
+	db *gorm.DB, logger *logrus.Logger, validate *validator.Validate, config *viper.Viper, userRepository *repository.UserRepository, postRepository *repository.PostRepository, commentRepository *repository.CommentRepository, reactionRepository *repository.ReactionRepository, bookmarkRepository *repository.BookmarkRepository, readingListRepository *repository.ReadingListRepository, seriesRepository *repository.SeriesRepository, postAuthorRepository *repository.PostAuthorRepository, followRepository *repository.FollowRepository, emailChangeRepository *repository.EmailChangeRepository, usernameHistoryRepository *repository.UsernameHistoryRepository, accountDeletionRepository *repository.AccountDeletionRepository, mediaRepository *repository.MediaRepository, storage storage.Storage, mddlwr *middleware.Middleware, loginThrottle *LoginThrottleUseCase, notification *NotificationUseCase, passwordPolicy *helper.PasswordPolicy, mailer mail.Mailer, publisher realtime.Publisher,
 ) *UserUseCase {
+	// Used to spend the same bcrypt time on unknown emails as on wrong passwords.
+	dummyPassword, err := bcrypt.GenerateFromPassword([]byte(uuid.New().String()), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Fatalf("Failed to generate dummy bcrypt hash : %+v", err)
+	}
+
 	return &UserUseCase{
-		DB:             db,
-		Log:            logger,
-		Validate:       validate,
-		UserRepository: userRepository,
-		Middleware:     mddlwr,
+		DB:                        db,
+		Log:                       logger,
+		Validate:                  validate,
+		Config:                    config,
+		UserRepository:            userRepository,
+		PostRepository:            postRepository,
+		CommentRepository:         commentRepository,
+		ReactionRepository:        reactionRepository,
+		BookmarkRepository:        bookmarkRepository,
+		ReadingListRepository:     readingListRepository,
+		SeriesRepository:          seriesRepository,
+		PostAuthorRepository:      postAuthorRepository,
+		FollowRepository:          followRepository,
+		EmailChangeRepository:     emailChangeRepository,
+		UsernameHistoryRepository: usernameHistoryRepository,
+		AccountDeletionRepository: accountDeletionRepository,
+		MediaRepository:           mediaRepository,
+		Storage:                   storage,
+		Middleware:                mddlwr,
+		LoginThrottle:             loginThrottle,
+		Notification:              notification,
+		Publisher:                 publisher,
+		PasswordPolicy:            passwordPolicy,
+		Mailer:                    mailer,
+		dummyPassword:             dummyPassword,
 	}
 }
 
@@ -44,6 +99,23 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.RegisterUserReq
 		return nil, fiber.ErrBadRequest
 	}
 
+	if c.reservedUsername(request.Username) {
+		c.Log.Warnf("Reserved username '%s' rejected", request.Username)
+		return nil, fiber.NewError(fiber.StatusConflict, "Username or email already used")
+	}
+
+	// An old username keeps redirecting to its owner for a while, nobody else can take it meanwhile.
+	history := new(entity.UsernameHistory)
+	if err := c.UsernameHistoryRepository.FindLatestByOldUsername(tx, history, request.Username, usernameRedirectSince(c.Config)); err == nil {
+		c.Log.Warnf("Username '%s' still redirects to user %s", request.Username, history.UserID)
+		return nil, fiber.NewError(fiber.StatusConflict, "Username or email already used")
+	}
+
+	if err := c.checkPassword(request.Password, request.Username, request.Email); err != nil {
+		c.Log.Warnf("Password rejected by policy : %+v", err)
+		return nil, err
+	}
+
 	password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	if err != nil {
 		c.Log.Warnf("Failed to generate bcrype hash : %+v", err)
@@ -58,6 +130,9 @@ func (c *UserUseCase) Create(ctx context.Context, request *model.RegisterUserReq
 	}
 	if err := c.UserRepository.Create(tx, user); err != nil {
 		c.Log.Warnf("Failed create user to database : %+v", err)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fiber.NewError(fiber.StatusConflict, "Username or email already used")
+		}
 		return nil, fiber.ErrInternalServerError
 	}
 
@@ -78,15 +153,23 @@ func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 		return nil, fiber.ErrBadRequest
 	}
 
+	if err := c.LoginThrottle.Check(ctx, request.Email, request.IP); err != nil {
+		c.Log.Warnf("Login locked for '%s' from %s : %+v", request.Email, request.IP, err)
+		return nil, err
+	}
+
 	user := new(entity.User)
 	if err := c.UserRepository.FindByEmail(tx, user, request.Email); err != nil {
 		c.Log.Warnf("Failed find user by email : %+v", err)
+		_ = bcrypt.CompareHashAndPassword(c.dummyPassword, []byte(request.Password))
+		c.LoginThrottle.RegisterFailure(ctx, request.Email, request.IP)
 		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password.")
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
 		c.Log.Warnf("Failed to compare user password with bcrype hash : %+v", err)
-		return nil, fiber.ErrUnauthorized
+		c.LoginThrottle.RegisterFailure(ctx, request.Email, request.IP)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password.")
 	}
 	auth := &model.Auth{
 		ID: user.ID,
@@ -102,71 +185,777 @@ func (c *UserUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 		return nil, fiber.ErrInternalServerError
 	}
 
+	c.LoginThrottle.RegisterSuccess(ctx, request.Email)
+
 	return &model.UserResponse{Token: token}, nil
 }
 
-func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserRequest) error {
+func (c *UserUseCase) Current(ctx context.Context, id string) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, id); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	response := converter.UserToResponse(user)
+	emailChange := new(entity.EmailChange)
+	if err := c.EmailChangeRepository.FindPendingByUserId(tx, emailChange, user.ID); err == nil {
+		response.PendingEmail = emailChange.NewEmail
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+// Update applies the profile changes right away, a new email is only stored as pending
+// and a confirmation link is sent to it once the transaction is committed, see ConfirmEmail.
+func (c *UserUseCase) Update(ctx context.Context, request *model.UpdateUserRequest) (*model.UserResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 	if err := c.Validate.Struct(request); err != nil {
 		c.Log.Warnf("Invalid request body  : %+v", err)
-		return fiber.ErrBadRequest
+		return nil, fiber.ErrBadRequest
 	}
 
-	if err := c.UserRepository.FindById(tx, &entity.User{}, request.ID); err != nil {
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
 		c.Log.Warnf("Failed find user by id : %+v", err)
-		return fiber.ErrNotFound
+		return nil, fiber.ErrNotFound
 	}
 
-	updatedUser := new(entity.User)
-	updatedUser.Name = request.Name
-	updatedUser.Email = request.Email
+	// Only the fields that were sent are written, so an empty string clears a profile field.
+	fields := map[string]any{}
+	if request.Name != "" {
+		fields["name"] = request.Name
+	}
+	setField := func(column string, value *string) {
+		if value != nil {
+			fields[column] = *value
+		}
+	}
+	setField("bio", request.Bio)
+	setField("avatar_url", request.AvatarURL)
+	setField("website", request.Website)
+	if links := request.SocialLinks; links != nil {
+		setField("twitter", links.Twitter)
+		setField("github", links.Github)
+		setField("linkedin", links.Linkedin)
+		setField("mastodon", links.Mastodon)
+	}
 	if request.Password != "" {
+		if err := c.checkPassword(request.Password, user.Username, request.Username, user.Email, request.Email); err != nil {
+			c.Log.Warnf("Password rejected by policy : %+v", err)
+			return nil, err
+		}
 		password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 		if err != nil {
 			c.Log.Warnf("Failed to generate bcrype hash : %+v", err)
-			return fiber.ErrInternalServerError
+			return nil, fiber.ErrInternalServerError
 		}
-		updatedUser.Password = string(password)
+		fields["password"] = string(password)
 	}
 
-	if err := c.UserRepository.Updates(tx, updatedUser, request.ID); err != nil {
-		c.Log.Warnf("Failed save user : %+v", err)
-		return fiber.ErrInternalServerError
+	if len(fields) > 0 {
+		if err := c.UserRepository.UpdateFields(tx, request.ID, fields); err != nil {
+			c.Log.Warnf("Failed save user : %+v", err)
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return nil, fiber.ErrConflict
+			}
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if request.Username != "" && request.Username != user.Username {
+		if err := c.changeUsername(tx, user, request.Username); err != nil {
+			return nil, err
+		}
+	}
+
+	var pendingEmail string
+	var confirmation *mail.Message
+	if request.Email != "" && !strings.EqualFold(request.Email, user.Email) {
+		message, err := c.requestEmailChange(tx, user, request.Email)
+		if err != nil {
+			return nil, err
+		}
+		pendingEmail = request.Email
+		confirmation = message
+	}
+
+	var changed []string
+	if request.Password != "" {
+		changed = append(changed, "password")
+	}
+	if request.Username != "" && request.Username != user.Username {
+		changed = append(changed, "username")
+	}
+	if pendingEmail != "" {
+		changed = append(changed, "email (waiting for confirmation)")
+	}
+	if len(changed) > 0 {
+		err := c.Notification.Notify(tx, entity.NotificationTypeAccountUpdated, &entity.Notification{
+			UserID:  user.ID,
+			Message: "Your account was updated: " + strings.Join(changed, ", "),
+		})
+		if err != nil {
+			c.Log.Warnf("Failed to notify account update : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// The confirmation only goes out once the email change it points to is stored.
+	if confirmation != nil {
+		if err := c.Mailer.Send(ctx, confirmation); err != nil {
+			c.Log.Warnf("Failed to send email change confirmation : %+v", err)
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Account updated but the confirmation email could not be sent")
+		}
+	}
+
+	response := converter.UserToResponse(user)
+	response.PendingEmail = pendingEmail
+	publish(ctx, c.Publisher, c.Log, realtime.TopicUser(user.ID), EventAccountUpdated, response)
+	return response, nil
+}
+
+func (c *UserUseCase) ConfirmEmail(ctx context.Context, request *model.ConfirmEmailRequest) (*model.UserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	emailChange := new(entity.EmailChange)
+	if err := c.EmailChangeRepository.FindByTokenHash(tx, emailChange, helper.HashToken(request.Token)); err != nil {
+		c.Log.Warnf("Failed find email change by token : %+v", err)
+		return nil, fiber.NewError(fiber.StatusNotFound, "Invalid confirmation token")
+	}
+
+	if emailChange.ExpiresAt.Before(time.Now()) {
+		return nil, fiber.NewError(fiber.StatusGone, "Confirmation token expired")
+	}
+
+	if err := c.UserRepository.FindByEmail(tx, new(entity.User), emailChange.NewEmail); err == nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "Email already used")
+	}
+
+	if err := c.UserRepository.Updates(tx, &entity.User{Email: emailChange.NewEmail}, emailChange.UserID); err != nil {
+		c.Log.Warnf("Failed update user email : %+v", err)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fiber.NewError(fiber.StatusConflict, "Email already used")
+		}
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.EmailChangeRepository.DeleteByUserId(tx, emailChange.UserID); err != nil {
+		c.Log.Warnf("Failed delete email changes : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	err := c.Notification.Notify(tx, entity.NotificationTypeAccountUpdated, &entity.Notification{
+		UserID:  emailChange.UserID,
+		Message: truncate("Your email address was changed to "+emailChange.NewEmail, 255),
+	})
+	if err != nil {
+		c.Log.Warnf("Failed to notify account update : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, emailChange.UserID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := converter.UserToResponse(user)
+	publish(ctx, c.Publisher, c.Log, realtime.TopicUser(user.ID), EventAccountUpdated, response)
+	return response, nil
+}
+
+// changeUsername keeps the old username in the history so author URLs using it keep redirecting,
+// a username still redirecting to another user cannot be taken.
+func (c *UserUseCase) changeUsername(tx *gorm.DB, user *entity.User, newUsername string) error {
+	if c.reservedUsername(newUsername) {
+		return fiber.NewError(fiber.StatusConflict, "Username already used")
+	}
+	if err := c.UserRepository.FindByUsername(tx, new(entity.User), newUsername); err == nil {
+		return fiber.NewError(fiber.StatusConflict, "Username already used")
+	}
+
+	history := new(entity.UsernameHistory)
+	if err := c.UsernameHistoryRepository.FindLatestByOldUsername(tx, history, newUsername, usernameRedirectSince(c.Config)); err == nil && history.UserID != user.ID {
+		return fiber.NewError(fiber.StatusConflict, "Username already used")
+	}
+
+	// Taking back one of your own old usernames ends its redirect.
+	if err := c.UsernameHistoryRepository.DeleteByUserIdAndOldUsername(tx, user.ID, newUsername); err != nil {
+		c.Log.Warnf("Failed delete username history : %+v", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := c.UserRepository.Updates(tx, &entity.User{Username: newUsername}, user.ID); err != nil {
+		c.Log.Warnf("Failed update username : %+v", err)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fiber.NewError(fiber.StatusConflict, "Username already used")
+		}
+		return fiber.ErrInternalServerError
+	}
+
+	if err := c.UsernameHistoryRepository.Create(tx, &entity.UsernameHistory{UserID: user.ID, OldUsername: user.Username}); err != nil {
+		c.Log.Warnf("Failed create username history : %+v", err)
 		return fiber.ErrInternalServerError
 	}
 
 	return nil
 }
 
-func (c *UserUseCase) Delete(ctx context.Context, id string) error {
+func (c *UserUseCase) requestEmailChange(tx *gorm.DB, user *entity.User, newEmail string) (*mail.Message, error) {
+	if err := c.UserRepository.FindByEmail(tx, new(entity.User), newEmail); err == nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "Email already used")
+	}
+
+	token, tokenHash, err := helper.GenerateToken()
+	if err != nil {
+		c.Log.Warnf("Failed to generate email change token : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.EmailChangeRepository.DeleteByUserId(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed delete previous email changes : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	emailChange := &entity.EmailChange{
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(configSeconds(c.Config, "EMAIL_CHANGE_TTL", 24*time.Hour)),
+	}
+	if err := c.EmailChangeRepository.Create(tx, emailChange); err != nil {
+		c.Log.Warnf("Failed create email change : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	link := fmt.Sprintf("%s/auth/email/confirm?token=%s", strings.TrimRight(c.Config.GetString("APP_BASE_URL"), "/"), token)
+	return &mail.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body:    fmt.Sprintf("Hi %s,\n\nOpen the link below to use this address for your account:\n\n%s\n\nIf you did not ask for this change you can ignore this email.\n", user.Name, link),
+	}, nil
+}
+
+func (c *UserUseCase) GetProfile(ctx context.Context, username string) (*model.ProfileResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindByUsername(tx, user, username); err != nil {
+		c.Log.Warnf("Failed find user by username '%s' : %+v", username, err)
+		if moved := findMovedUsername(tx, c.Config, c.UsernameHistoryRepository, username); moved != nil {
+			return nil, moved
+		}
+		return nil, fiber.ErrNotFound
+	}
+
+	posts, total, err := c.PostRepository.Find(tx, &model.SearchPostRequest{
+		Username: user.Username,
+		Sort:     "latest",
+		Paginate: model.Pagination{Page: 1, Size: 5},
+	})
+	if err != nil {
+		c.Log.Warnf("Failed to get latest posts of '%s' : %+v", username, err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	followers, following, err := c.FollowRepository.CountFollows(tx, user.ID)
+	if err != nil {
+		c.Log.Warnf("Failed to count follows of '%s' : %+v", username, err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	latestPosts := make([]model.PostResponse, len(posts))
+	for i, post := range posts {
+		latestPosts[i] = *converter.PostToResponse(&post)
+	}
+
+	response := converter.UserToProfileResponse(user, total, latestPosts)
+	response.FollowerCount = followers
+	response.FollowingCount = following
+	return response, nil
+}
+
+// DeleteCurrent lets users delete their own account after confirming their password. Transferring
+// posts is left to administrators so nobody gets posts pushed onto them without consent.
+func (c *UserUseCase) DeleteCurrent(ctx context.Context, request *model.DeleteUserRequest) (*model.DeleteUserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if request.Posts == entity.PostsStrategyTransfer {
+		c.Log.Warnf("Post transfer refused for user %s deleting their own account", request.ID)
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Posts can only be transferred by an administrator")
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+		c.Log.Warnf("Failed to compare user password with bcrype hash : %+v", err)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid password.")
+	}
+
+	response, err := c.deleteAccount(tx, user, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+// Delete is the admin variant of DeleteCurrent, it does not ask for the password.
+func (c *UserUseCase) Delete(ctx context.Context, request *model.DeleteUserRequest) (*model.DeleteUserResponse, error) {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user id or posts option")
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindById(tx, user, request.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrNotFound
+	}
+
+	response, err := c.deleteAccount(tx, user, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.Notification.Notify(tx, entity.NotificationTypeAccountDeleted, &entity.Notification{
+		UserID:  user.ID,
+		Message: fmt.Sprintf("Your account was deleted by an administrator, it can be restored until %s", response.PurgeAfter.Format("2006-01-02")),
+	})
+	if err != nil {
+		c.Log.Warnf("Failed to notify account deletion : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		c.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+// Restore brings back a deleted account during its restore window. Posts deleted with the account
+// come back too, transferred or anonymized posts stay where they are.
+func (c *UserUseCase) Restore(ctx context.Context, id string) (*model.UserResponse, error) {
 	tx := c.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	if err := c.Validate.Var(id, "uuid4"); err != nil {
 		c.Log.Warnf("Invalid user id : %+v", err)
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid user id")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid user id")
 	}
 
 	user := new(entity.User)
-	if err := c.UserRepository.FindById(tx, user, id); err != nil {
-		c.Log.Warnf("Failed find user by id : %+v", err)
-		return fiber.ErrNotFound
+	if err := c.UserRepository.FindDeletedById(tx, user, id); err != nil {
+		c.Log.Warnf("Failed find deleted user by id : %+v", err)
+		return nil, fiber.ErrNotFound
 	}
 
-	if err := c.UserRepository.Delete(tx, user); err != nil {
-		c.Log.Warnf("Failed delete user : %+v", err)
-		return fiber.ErrInternalServerError
+	deletion := new(entity.AccountDeletion)
+	if err := c.AccountDeletionRepository.FindByUserId(tx, deletion, user.ID); err != nil || deletion.PurgeAfter.Before(time.Now()) {
+		c.Log.Warnf("Restore window of user '%s' is over : %+v", user.ID, err)
+		return nil, fiber.NewError(fiber.StatusGone, "Restore window is over")
+	}
+
+	if err := c.UserRepository.Restore(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed restore user : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if deletion.PostsStrategy == entity.PostsStrategyDelete {
+		if err := c.PostRepository.RestoreByUserId(tx, user.ID, deletion.DeletedAt); err != nil {
+			c.Log.Warnf("Failed restore posts : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.CommentRepository.RestoreByUserId(tx, user.ID, deletion.DeletedAt); err != nil {
+		c.Log.Warnf("Failed restore comments : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.PostRepository.RecountCommentsByCommenter(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed recount comments : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.AccountDeletionRepository.DeleteByUserId(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed delete account deletion : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	err := c.Notification.Notify(tx, entity.NotificationTypeAccountRestored, &entity.Notification{
+		UserID:  user.ID,
+		Message: "Your account was restored",
+	})
+	if err != nil {
+		c.Log.Warnf("Failed to notify account restore : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.UserRepository.FindById(tx, user, user.ID); err != nil {
+		c.Log.Warnf("Failed find user by id : %+v", err)
+		return nil, fiber.ErrInternalServerError
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		c.Log.Warnf("Failed commit transaction : %+v", err)
-		return fiber.ErrInternalServerError
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.UserToResponse(user), nil
+}
+
+// RestoreCurrent lets users restore their own deleted account with their credentials.
+func (c *UserUseCase) RestoreCurrent(ctx context.Context, request *model.LoginUserRequest) (*model.UserResponse, error) {
+	if err := c.Validate.Struct(request); err != nil {
+		c.Log.Warnf("Invalid request body : %+v", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if err := c.LoginThrottle.Check(ctx, request.Email, request.IP); err != nil {
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindDeletedByEmail(c.DB.WithContext(ctx), user, request.Email); err != nil {
+		_ = bcrypt.CompareHashAndPassword(c.dummyPassword, []byte(request.Password))
+		c.LoginThrottle.RegisterFailure(ctx, request.Email, request.IP)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password.")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+		c.LoginThrottle.RegisterFailure(ctx, request.Email, request.IP)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid email or password.")
+	}
+	c.LoginThrottle.RegisterSuccess(ctx, request.Email)
+
+	return c.Restore(ctx, user.ID)
+}
+
+// PurgeDeleted permanently removes the accounts whose restore window is over.
+func (c *UserUseCase) PurgeDeleted(ctx context.Context) error {
+	deletions, err := c.AccountDeletionRepository.FindPurgeable(c.DB.WithContext(ctx), time.Now())
+	if err != nil {
+		c.Log.Warnf("Failed to find purgeable accounts : %+v", err)
+		return err
+	}
+
+	for _, deletion := range deletions {
+		if err := c.purgeAccount(ctx, deletion.UserID); err != nil {
+			c.Log.Warnf("Failed to purge user '%s' : %+v", deletion.UserID, err)
+			continue
+		}
+		c.Log.Infof("Purged deleted user '%s'", deletion.UserID)
 	}
 
 	return nil
 }
+
+// purgeAccount hard deletes what is left of a deleted user. Media still used by posts that went
+// to another author are handed over to that author, the files of the rest are removed once committed.
+func (c *UserUseCase) purgeAccount(ctx context.Context, userId string) error {
+	tx := c.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := c.UserRepository.FindDeletedById(tx, user, userId); err == nil {
+		if err := c.LoginThrottle.LoginThrottleRepository.DeleteByIdentifier(tx, LoginThrottleScopeAccount, normalizeEmail(user.Email)); err != nil {
+			return err
+		}
+	}
+	if err := c.CommentRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.ReactionRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.BookmarkRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.ReadingListRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.SeriesRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.PostAuthorRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.FollowRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.Notification.NotificationRepository.DeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := c.PostRepository.HardDeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	keys, err := c.purgeMedia(tx, userId)
+	if err != nil {
+		return err
+	}
+	if err := c.EmailChangeRepository.DeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", userId).Delete(&entity.UsernameHistory{}).Error; err != nil {
+		return err
+	}
+	if err := c.UserRepository.HardDelete(tx, userId); err != nil {
+		return err
+	}
+	if err := c.AccountDeletionRepository.DeleteByUserId(tx, userId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	removeFiles(c.Storage, c.Log, keys)
+	return nil
+}
+
+// purgeMedia re-owns or deletes the media of userId and returns the storage keys of the deleted ones.
+func (c *UserUseCase) purgeMedia(tx *gorm.DB, userId string) ([]string, error) {
+	media, err := c.MediaRepository.FindAllByUserId(tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for i := range media {
+		owner, err := c.MediaRepository.FindOtherUserId(tx, media[i].ID, userId)
+		if err != nil {
+			return nil, err
+		}
+		if owner != "" {
+			if err := c.MediaRepository.Updates(tx, &entity.Media{UserID: owner}, media[i].ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err := c.MediaRepository.DeleteVariants(tx, media[i].ID); err != nil {
+			return nil, err
+		}
+		if err := c.MediaRepository.Delete(tx, &media[i]); err != nil {
+			return nil, err
+		}
+		keys = append(keys, media[i].StorageKey)
+		for _, variant := range media[i].Variants {
+			keys = append(keys, variant.StorageKey)
+		}
+	}
+	return keys, nil
+}
+
+// deleteAccount moves, anonymizes or deletes the posts of user and soft deletes it, all within tx.
+func (c *UserUseCase) deleteAccount(tx *gorm.DB, user *entity.User, request *model.DeleteUserRequest) (*model.DeleteUserResponse, error) {
+	// Truncated so the timestamp survives the round trip to MySQL and can be matched on restore.
+	now := time.Now().Truncate(time.Second)
+	deletion := &entity.AccountDeletion{
+		UserID:        user.ID,
+		PostsStrategy: request.Posts,
+		DeletedAt:     now,
+		PurgeAfter:    now.AddDate(0, 0, c.restoreDays()),
+	}
+
+	switch request.Posts {
+	case entity.PostsStrategyTransfer:
+		target := new(entity.User)
+		if err := c.UserRepository.FindByUsername(tx, target, request.TransferTo); err != nil {
+			c.Log.Warnf("Failed find transfer target '%s' : %+v", request.TransferTo, err)
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Unknown user to transfer posts to")
+		}
+		if target.ID == user.ID {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Cannot transfer posts to the deleted user")
+		}
+		if err := c.PostRepository.TransferByUserId(tx, user.ID, target.ID); err != nil {
+			c.Log.Warnf("Failed transfer posts : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := c.SeriesRepository.TransferByUserId(tx, user.ID, target.ID); err != nil {
+			c.Log.Warnf("Failed transfer series : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		err := c.Notification.Notify(tx, entity.NotificationTypePostsTransferred, &entity.Notification{
+			UserID:  target.ID,
+			Message: truncate(fmt.Sprintf("The posts of %s were transferred to you", user.Name), 255),
+			Link:    "/posts/" + target.Username,
+		})
+		if err != nil {
+			c.Log.Warnf("Failed to notify posts transfer : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		deletion.TransferredTo = target.ID
+	case entity.PostsStrategyAnonymize:
+		placeholder, err := c.formerAuthor(tx)
+		if err != nil {
+			c.Log.Warnf("Failed to get former author placeholder : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		if placeholder.ID == user.ID {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Cannot delete the former author placeholder")
+		}
+		if err := c.PostRepository.TransferByUserId(tx, user.ID, placeholder.ID); err != nil {
+			c.Log.Warnf("Failed anonymize posts : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		if err := c.SeriesRepository.TransferByUserId(tx, user.ID, placeholder.ID); err != nil {
+			c.Log.Warnf("Failed anonymize series : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+		deletion.TransferredTo = placeholder.ID
+	case entity.PostsStrategyDelete:
+		if err := c.PostRepository.DeleteByUserId(tx, user.ID, now); err != nil {
+			c.Log.Warnf("Failed delete posts : %+v", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := c.CommentRepository.DeleteByUserId(tx, user.ID, now); err != nil {
+		c.Log.Warnf("Failed delete comments : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.PostRepository.RecountCommentsByCommenter(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed recount comments : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.EmailChangeRepository.DeleteByUserId(tx, user.ID); err != nil {
+		c.Log.Warnf("Failed delete email changes : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.UserRepository.Delete(tx, user); err != nil {
+		c.Log.Warnf("Failed delete user : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := c.AccountDeletionRepository.Save(tx, deletion); err != nil {
+		c.Log.Warnf("Failed save account deletion : %+v", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.DeleteUserResponse{
+		ID:         user.ID,
+		Posts:      deletion.PostsStrategy,
+		PurgeAfter: &deletion.PurgeAfter,
+	}, nil
+}
+
+// formerAuthor returns the placeholder user anonymized posts are attached to, creating it on first use.
+// It is found by its flag, never by username, so nobody can pose as it.
+func (c *UserUseCase) formerAuthor(tx *gorm.DB) (*entity.User, error) {
+	username := c.formerAuthorUsername()
+
+	// Nobody knows this password, the placeholder cannot log in.
+	password, err := bcrypt.GenerateFromPassword([]byte(uuid.New().String()), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := new(entity.User)
+	attrs := &entity.User{
+		ID:       uuid.New().String(),
+		Name:     "Former author",
+		Username: username,
+		Email:    username + "@users.invalid",
+		Password: string(password),
+	}
+	if err := c.UserRepository.FirstOrCreatePlaceholder(tx, user, attrs); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (c *UserUseCase) formerAuthorUsername() string {
+	if username := c.Config.GetString("FORMER_AUTHOR_USERNAME"); username != "" {
+		return username
+	}
+	return "former-author"
+}
+
+// reservedUsername reports whether username is kept for the former author placeholder.
+func (c *UserUseCase) reservedUsername(username string) bool {
+	return strings.EqualFold(username, c.formerAuthorUsername())
+}
+
+func (c *UserUseCase) restoreDays() int {
+	if days := c.Config.GetInt("ACCOUNT_RESTORE_DAYS"); days > 0 {
+		return days
+	}
+	return 30
+}
+
+// checkPassword applies the password policy, it is shared by every flow that sets a password.
+func (c *UserUseCase) checkPassword(password string, personal ...string) error {
+	messages := c.PasswordPolicy.Check(password, personal...)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	fields := make([]model.FieldError, len(messages))
+	for i, message := range messages {
+		fields[i] = model.FieldError{Field: "password", Message: message}
+	}
+	return &model.ValidationError{Fields: fields}
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"go-blog/internal/entity"
+	"go-blog/internal/repository"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UsernameMovedError is returned when a lookup hits a username that was recently changed,
+// Username is the current one so the caller can redirect to it.
+type UsernameMovedError struct {
+	Username string
+}
+
+func (e *UsernameMovedError) Error() string {
+	return fmt.Sprintf("Username moved to '%s'", e.Username)
+}
+
+// findMovedUsername returns a *UsernameMovedError when username belonged to a user who renamed
+// within USERNAME_REDIRECT_DAYS, and nil otherwise.
+func findMovedUsername(tx *gorm.DB, config *viper.Viper, historyRepository *repository.UsernameHistoryRepository, username string) error {
+	history := new(entity.UsernameHistory)
+	if err := historyRepository.FindLatestByOldUsername(tx, history, username, usernameRedirectSince(config)); err != nil {
+		return nil
+	}
+	if history.User.Username == "" || history.User.Username == username {
+		return nil
+	}
+	return &UsernameMovedError{Username: history.User.Username}
+}
+
+func usernameRedirectSince(config *viper.Viper) time.Time {
+	days := config.GetInt("USERNAME_REDIRECT_DAYS")
+	if days <= 0 {
+		days = 90
+	}
+	return time.Now().AddDate(0, 0, -days)
+}
@@ -0,0 +1,113 @@
+package theme
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// A theme is a directory with templates/, the Fiber html templates, and assets/, served under /assets.
+
+//go:embed default
+var embedded embed.FS
+
+// Default is the built-in theme, compiled into the binary.
+func Default() fs.FS {
+	sub, err := fs.Sub(embedded, "default")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// Open lays the theme in dir over the default theme, so a theme only has to ship the templates and
+// assets it changes. An empty dir is the default theme alone.
+func Open(dir string) (fs.FS, error) {
+	if dir == "" {
+		return Default(), nil
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("theme %s is not a directory", dir)
+	}
+	return &overlay{upper: os.DirFS(dir), lower: Default()}, nil
+}
+
+// overlay reads files from upper first and falls back to lower, directories list the entries of both.
+type overlay struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+func (o *overlay) Open(name string) (fs.File, error) {
+	file, err := o.upper.Open(name)
+	if err != nil {
+		return o.lower.Open(name)
+	}
+
+	info, err := file.Stat()
+	if err != nil || !info.IsDir() {
+		return file, err
+	}
+
+	entries, err := o.ReadDir(name)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &overlayDir{File: file, entries: entries}, nil
+}
+
+func (o *overlay) ReadDir(name string) ([]fs.DirEntry, error) {
+	upper, upperErr := fs.ReadDir(o.upper, name)
+	lower, lowerErr := fs.ReadDir(o.lower, name)
+	if upperErr != nil && lowerErr != nil {
+		return nil, upperErr
+	}
+	if upperErr != nil && !errors.Is(upperErr, fs.ErrNotExist) {
+		return nil, upperErr
+	}
+
+	merged := make(map[string]fs.DirEntry, len(upper)+len(lower))
+	for _, entry := range lower {
+		merged[entry.Name()] = entry
+	}
+	for _, entry := range upper {
+		merged[entry.Name()] = entry
+	}
+
+	entries := make([]fs.DirEntry, 0, len(merged))
+	for _, entry := range merged {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// overlayDir is a directory of upper listing the merged entries.
+type overlayDir struct {
+	fs.File
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}